@@ -2,13 +2,18 @@ package bigv
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
+	"math"
+	"math/rand"
 	"net/http"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
@@ -18,11 +23,60 @@ const bigvAuthUri = "https://auth.bytemark.co.uk/session"
 const bigvTimeout = 20
 
 type client struct {
-	account  string
-	user     string
-	password string
-	http     *http.Client
-	session  string
+	account    string
+	user       string
+	password   string
+	totp       string
+	totpSecret string
+	apiUrl     string
+	authUrl    string
+	apiVersion string
+	http       *http.Client
+	httpInit   sync.Once
+	session    string
+	// The session is an API key, which can't be refreshed
+	apiKeyAuth bool
+
+	// Seconds before giving up on a request
+	httpTimeout int
+
+	// Semaphore limiting concurrent requests to bigv
+	operations chan struct{}
+
+	// How concurrent VM creates are allowed to be
+	createPipelineMode string
+	creates            chan struct{}
+
+	// How often to check on VMs we're waiting for
+	provisionPollInterval time.Duration
+	resizePollInterval    time.Duration
+
+	// Retrying 503s during bigv maintenance
+	maintenanceRetryAttempts int
+	maintenanceRetryMaxWait  time.Duration
+
+	// Backing off from transient server errors
+	maxRetries             int
+	retryBackoffMultiplier float64
+
+	// Backing off from 503s without a Retry-After, when bigv is overloaded
+	max503Retries int
+
+	// Check os names with bigv at plan time
+	validateOs bool
+
+	// Log every request and response in full, with credentials redacted
+	debugHttp bool
+
+	// For VMs that don't set their own
+	defaultGroup        string
+	defaultZone         string
+	defaultOs           string
+	defaultDiscSize     int
+	defaultStorageGrade string
+
+	// Cancelled when terraform is interrupted
+	stop context.Context
 }
 
 var sessions sync.Mutex
@@ -30,6 +84,7 @@ var sessions sync.Mutex
 type credentials struct {
 	Username string `json:"username"`
 	Password string `json:"password"`
+	Totp     string `json:"2fa,omitempty"`
 }
 
 func (c *client) newSession() error {
@@ -38,6 +93,16 @@ func (c *client) newSession() error {
 	cr := credentials{
 		Username: c.user,
 		Password: c.password,
+		Totp:     c.totp,
+	}
+
+	// Codes expire, so with the secret we can make a fresh one for every session
+	if c.totpSecret != "" {
+		code, err := totpCode(c.totpSecret, time.Now())
+		if err != nil {
+			return err
+		}
+		cr.Totp = code
 	}
 
 	body, err := json.Marshal(cr)
@@ -46,46 +111,173 @@ func (c *client) newSession() error {
 		return err
 	}
 
-	l.Printf("Requesting new session at: %s", bigvAuthUri)
-	req, _ := http.NewRequest("POST", bigvAuthUri, bytes.NewBuffer(body))
+	l.Printf("Requesting new session at: %s", c.authUrl)
+	req, _ := http.NewRequest("POST", c.authUrl, bytes.NewBuffer(body))
+	req = req.WithContext(c.stopContext())
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add("Accept", "text/plain")
 
-	if resp, err := c.http.Do(req); err != nil {
+	c.httpInit.Do(c.initHttp)
+	resp, err := c.http.Do(req)
+	if err != nil {
 		return err
-	} else {
+	}
 
-		body, _ := ioutil.ReadAll(resp.Body)
-		defer resp.Body.Close()
+	// Always close the body when done
+	defer resp.Body.Close()
 
-		c.session = string(body)
-		l.Printf("Got back session Id: %s", c.session)
+	body, err = ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Bigv auth returned HTTP Status %d: %s", resp.StatusCode, body)
+	}
+
+	c.session = string(body)
+	l.Printf("Got back a new session")
+
+	return nil
+}
+
+// apiUri
+// v1 is the original bigv API, which lives at the root.
+// Later versions get their own path.
+// Paths are added on with a leading /, so there's never a trailing one here.
+func (c *client) apiUri() string {
+	apiUrl := strings.TrimRight(c.apiUrl, "/")
+	if c.apiVersion == "" || c.apiVersion == "v1" {
+		return apiUrl
+	}
+
+	return fmt.Sprintf("%s/%s", apiUrl, c.apiVersion)
+}
+
+// ensureSession
+// Only one caller gets a new session, the rest wait for it.
+// Failing to log in must still let them through, or every request after blocks forever.
+func (c *client) ensureSession() error {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	// Check again, in case it's been fixed by something we were blocking on
+	if c.session == "" {
+		return c.newSession()
 	}
 
 	return nil
 }
 
+// refreshSession
+// Replaces an expired session, long runs outlive them.
+// Concurrent requests all 401 together, so only the first gets a new one and the rest use that.
+func (c *client) refreshSession(expired string) error {
+	sessions.Lock()
+	defer sessions.Unlock()
+
+	if c.session != expired {
+		return nil
+	}
+
+	return c.newSession()
+}
+
 func (c *client) do(req *http.Request) (*http.Response, error) {
+	return c.doWithTimeout(req, 0)
+}
+
+// doJson
+// For the simpler resources, which just send and get back json.
+// in is sent as the body unless it's nil, and out is decoded from the response unless it's nil.
+// The status comes back with any error, so callers can spot 404s.
+func (c *client) doJson(method, url string, in, out interface{}) (int, error) {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return 0, err
+		}
+	}
+
+	// Bodies are only logged for debug_http, by loggingTransport
+	log.Printf("[DEBUG] Requesting %s %s", method, url)
+
+	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		if resp != nil {
+			return resp.StatusCode, err
+		}
+		return 0, err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return resp.StatusCode, err
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+// initHttp
+// The http client every request goes through
+func (c *client) initHttp() {
+	timeout := c.httpTimeout
+	if timeout <= 0 {
+		timeout = bigvTimeout
+	}
+	c.http = &http.Client{
+		Timeout: time.Second * time.Duration(timeout),
+	}
+
+	if c.debugHttp {
+		c.http.Transport = &loggingTransport{
+			transport: http.DefaultTransport,
+			// With api_key auth, the session is the API key
+			secrets: func() []string {
+				return []string{c.password, c.session, c.totp, c.totpSecret}
+			},
+		}
+	}
+}
+
+// doWithTimeout
+// As do, but a non zero timeout overrides the client's default for this request
+func (c *client) doWithTimeout(req *http.Request, timeout time.Duration) (*http.Response, error) {
 	l := log.New(os.Stderr, "", 0)
 
-	if c.http == nil {
-		// Initialization
-		c.http = &http.Client{
-			Timeout: time.Second * bigvTimeout,
+	// Concurrent requests share the one http client, so only the first sets it up
+	c.httpInit.Do(c.initHttp)
+
+	httpClient := c.http
+	if timeout > 0 {
+		httpClient = &http.Client{
+			Transport: c.http.Transport,
+			Timeout:   timeout,
 		}
 	}
 
 	if c.session == "" {
-		sessions.Lock()
-		// Check again, in case it's been fixed by something we were blocking on
-		if c.session == "" {
-			if err := c.newSession(); err != nil {
-				return nil, err
-			}
+		if err := c.ensureSession(); err != nil {
+			return nil, err
 		}
-		sessions.Unlock()
 	}
 
+	// Interrupting terraform abandons the request, and any retries of it
+	ctx := c.stopContext()
+	req = req.WithContext(ctx)
+
 	req.Header.Set("Content-Type", "application/json")
 
 	// We're going to potentially do this again, so we need to copy the body
@@ -94,16 +286,30 @@ func (c *client) do(req *http.Request) (*http.Response, error) {
 		body, _ = ioutil.ReadAll(req.Body)
 	}
 
-	for i := 0; i < 3; i++ {
+	authRetried := false
+	// Every kind of retry comes out of the one count, each with its own limit
+	retries := 0
+	start := time.Now()
+	for {
 		if len(body) > 0 {
 			req.Body = ioutil.NopCloser(bytes.NewReader(body))
 		}
 
 		// Set inside for loop because we regenerate it if we 401
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.session))
-		l.Printf("Using Session Id: %s", c.session)
+		session := c.session
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", session))
 
-		resp, err := c.http.Do(req)
+		if c.operations != nil {
+			select {
+			case c.operations <- struct{}{}:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+		resp, err := httpClient.Do(req)
+		if c.operations != nil {
+			<-c.operations
+		}
 
 		if err != nil {
 			return resp, err
@@ -116,18 +322,208 @@ func (c *client) do(req *http.Request) (*http.Response, error) {
 
 		// Otherwise we need to massage and deal with auth retries
 
-		if resp.StatusCode == 401 && i == 0 {
+		if resp.StatusCode == 401 && !authRetried && !c.apiKeyAuth {
+			authRetried = true
+			// We're not going to use this response
+			resp.Body.Close()
+
 			l.Printf("HTTP 401. Retrying with a new session id")
-			time.Sleep(1 * time.Second)
-			c.newSession()
+			if err := sleepContext(ctx, 1*time.Second); err != nil {
+				return nil, err
+			}
+			if err := c.refreshSession(session); err != nil {
+				return nil, err
+			}
 			continue
 		}
 
+		// Server errors, only for requests that are safe to send twice
+		if retryableStatus(req.Method, resp.StatusCode) {
+			limit, wait := c.retryWait(resp, retries+1)
+			if retries < limit {
+				retries++
+				resp.Body.Close()
+
+				if resp.StatusCode == http.StatusServiceUnavailable {
+					l.Printf("BigV returned 503 Service Unavailable, retrying after %ds (attempt %d/%d)", int(wait.Seconds()), retries, limit)
+				} else {
+					l.Printf("BigV returned HTTP Status %d, retrying after %s (attempt %d/%d)", resp.StatusCode, wait, retries, limit)
+				}
+				if err := sleepContext(ctx, wait); err != nil {
+					return nil, err
+				}
+				continue
+			}
+
+			if resp.StatusCode == http.StatusServiceUnavailable && retries > 0 {
+				body, _ := ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				return resp, fmt.Errorf("Bigv returned 503 Service Unavailable after %d retries over %s: %s",
+					retries, time.Since(start).Round(time.Second), body)
+			}
+		}
+
 		// Any other http error. Try to get more about it
 		body, _ := ioutil.ReadAll(resp.Body)
-		defer resp.Body.Close()
-		return resp, fmt.Errorf("Bigv returned HTTP Status %d: %s", resp.StatusCode, body)
+		resp.Body.Close()
+		return resp, bigvError(resp.StatusCode, body)
+	}
+}
+
+// BigVAPIError
+// bigv's 422 responses say which fields it didn't like, as json of field names to errors
+type BigVAPIError struct {
+	StatusCode int
+	Fields     map[string][]string
+}
+
+func (e *BigVAPIError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	lines := []string{fmt.Sprintf("Bigv returned HTTP Status %d, rejecting:", e.StatusCode)}
+	for _, field := range fields {
+		for _, message := range e.Fields[field] {
+			lines = append(lines, fmt.Sprintf("  %s: %s", field, message))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// bigvError
+// The error for a failed request, with field by field detail for validation failures
+func bigvError(status int, body []byte) error {
+	if status == http.StatusUnprocessableEntity {
+		if fields, ok := parseBigvFieldErrors(body); ok {
+			return &BigVAPIError{StatusCode: status, Fields: fields}
+		}
+	}
+
+	return fmt.Errorf("Bigv returned HTTP Status %d: %s", status, body)
+}
+
+// parseBigvFieldErrors
+// Each field has either one error or a list of them
+func parseBigvFieldErrors(body []byte) (map[string][]string, bool) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(body, &raw); err != nil || len(raw) == 0 {
+		return nil, false
+	}
+
+	fields := make(map[string][]string, len(raw))
+	for field, v := range raw {
+		var message string
+		if err := json.Unmarshal(v, &message); err == nil {
+			fields[field] = []string{message}
+			continue
+		}
+
+		var messages []string
+		if err := json.Unmarshal(v, &messages); err != nil {
+			return nil, false
+		}
+		fields[field] = messages
+	}
+
+	return fields, true
+}
+
+// stopContext
+// Clients not made by the provider never stop
+func (c *client) stopContext() context.Context {
+	if c.stop == nil {
+		return context.Background()
+	}
+
+	return c.stop
+}
+
+// sleepContext
+// time.Sleep, unless terraform is interrupted first
+func sleepContext(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// retryableStatus
+// POSTs could create things twice, and 4xx errors will only fail again
+func retryableStatus(method string, status int) bool {
+	switch method {
+	case "GET", "PUT", "DELETE":
+	default:
+		return false
+	}
+
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// retryWait
+// How many times a failure like resp can be retried, and how long to wait before this attempt.
+// Planned maintenance says when to come back, other 503s mean bigv is overloaded.
+func (c *client) retryWait(resp *http.Response, attempt int) (int, time.Duration) {
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		return c.maxRetries, c.retryBackoff(attempt)
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		return c.maintenanceRetryAttempts, c.maintenanceWait(retryAfter)
+	}
+
+	return c.max503Retries, unavailableBackoff(attempt)
+}
+
+// retryBackoff
+// 1s before the first retry, then multiplied up each time: 1s, 2s, 4s by default.
+// Up to half as much again is added at random, so concurrent requests don't all retry together.
+func (c *client) retryBackoff(attempt int) time.Duration {
+	wait := time.Duration(float64(time.Second) * math.Pow(c.retryBackoffMultiplier, float64(attempt-1)))
+
+	return wait + time.Duration(rand.Int63n(int64(wait)/2+1))
+}
+
+// unavailableBackoff
+// 2^attempt seconds, so 2s before the first retry, but never more than a minute
+func unavailableBackoff(attempt int) time.Duration {
+	if attempt >= 6 {
+		return time.Minute
+	}
+
+	return time.Duration(1<<uint(attempt)) * time.Second
+}
+
+// maintenanceWait
+// How long a Retry-After header says to wait, which is either seconds or a date.
+// Never longer than maintenanceRetryMaxWait though.
+func (c *client) maintenanceWait(retryAfter string) time.Duration {
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(retryAfter); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(retryAfter); err == nil {
+		wait = date.Sub(time.Now())
+	}
+
+	if wait < 0 {
+		wait = 0
+	}
+	if wait > c.maintenanceRetryMaxWait {
+		wait = c.maintenanceRetryMaxWait
 	}
 
-	return nil, errors.New("Unexpected error in HTTP client, this should not happen")
+	return wait
 }
@@ -0,0 +1,627 @@
+package bigv
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// testBigvClient
+// A client already logged in to a fake bigv, close the server when done
+func testBigvClient(handler http.HandlerFunc) (*client, *httptest.Server) {
+	server := httptest.NewServer(handler)
+
+	return &client{
+		account:  "myaccount",
+		user:     "user",
+		password: "password",
+		apiUrl:   server.URL,
+		authUrl:  server.URL + "/session",
+		session:  "session",
+	}, server
+}
+
+// testBigvCall
+// A request the fake bigv expects, and its answer. The path includes any query, the status defaults to 200
+type testBigvCall struct {
+	method   string
+	path     string
+	status   int
+	response string
+}
+
+// testBigvCalls
+// A client for a fake bigv that expects exactly these calls, in order.
+// sent has the json body of each call, nil for those without one. Call check once done, then close the server
+func testBigvCalls(t *testing.T, calls []testBigvCall) (c *client, server *httptest.Server, sent []map[string]interface{}, check func()) {
+	made := 0
+	sent = make([]map[string]interface{}, len(calls))
+
+	c, server = testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		request := r.Method + " " + r.URL.RequestURI()
+		if made >= len(calls) {
+			t.Errorf("unexpected request %s after the %d expected", request, len(calls))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		call := calls[made]
+		if expected := call.method + " " + call.path; request != expected {
+			t.Errorf("request %d: expected %s, got %s", made, expected, request)
+		}
+
+		if r.Method == "POST" || r.Method == "PUT" {
+			body := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&body)
+			sent[made] = body
+		}
+		made++
+
+		if call.status != 0 {
+			w.WriteHeader(call.status)
+		}
+		w.Write([]byte(call.response))
+	})
+
+	check = func() {
+		for _, call := range calls[made:] {
+			t.Errorf("expected %s %s, which never came", call.method, call.path)
+		}
+	}
+
+	return c, server, sent, check
+}
+
+// testBigvRoundTrip
+// Creates r from raw, sets changes and updates it, then deletes it, all against a fake bigv expecting exactly calls.
+// Returns the resource's data and the json sent with each call
+func testBigvRoundTrip(t *testing.T, r *schema.Resource, raw, changes map[string]interface{}, calls []testBigvCall) (*schema.ResourceData, []map[string]interface{}) {
+	c, server, sent, check := testBigvCalls(t, calls)
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+
+	if err := r.Create(d, c); err != nil {
+		t.Fatalf("create: %s", err)
+	}
+	for k, v := range changes {
+		d.Set(k, v)
+	}
+	if err := r.Update(d, c); err != nil {
+		t.Fatalf("update: %s", err)
+	}
+	if err := r.Delete(d, c); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+
+	check()
+	return d, sent
+}
+
+// testBigvNotFound
+// Checks that reading or deleting r as id, from raw, removes it from state once bigv has lost it
+func testBigvNotFound(t *testing.T, r *schema.Resource, raw map[string]interface{}, id string) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+	d.SetId(id)
+
+	if err := r.Read(d, c); err != nil {
+		t.Fatalf("read: %s", err)
+	}
+	if d.Id() != "" {
+		t.Error("expected it to be removed from state once bigv lost it")
+	}
+
+	// Deleting what's already gone has done its job
+	d.SetId(id)
+	if err := r.Delete(d, c); err != nil {
+		t.Fatalf("delete: %s", err)
+	}
+	if d.Id() != "" {
+		t.Error("expected deleting it to succeed once bigv lost it")
+	}
+}
+
+// testBigvSent
+// Checks body has exactly the expected json, numbers being float64s
+func testBigvSent(t *testing.T, name string, body, expected map[string]interface{}) {
+	if len(body) != len(expected) {
+		t.Errorf("%s: expected %v, got %v", name, expected, body)
+	}
+	for k, v := range expected {
+		if body[k] != v {
+			t.Errorf("%s: expected %s %v, got %v", name, k, v, body[k])
+		}
+	}
+}
+
+func TestClientConcurrentOperations(t *testing.T) {
+	var mu sync.Mutex
+	running, most := 0, 0
+
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		running++
+		if running > most {
+			most = running
+		}
+		mu.Unlock()
+
+		time.Sleep(20 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+	})
+	defer server.Close()
+
+	c.operations = make(chan struct{}, 2)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+			resp, err := c.do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if most > 2 {
+		t.Fatalf("expected at most 2 concurrent requests, got %d", most)
+	}
+	if most < 2 {
+		t.Fatalf("expected requests to run 2 at a time, got %d", most)
+	}
+}
+
+func TestClientRetries401WithNewSession(t *testing.T) {
+	var bodies []string
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/session" {
+			w.Write([]byte("new-session"))
+			return
+		}
+
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if r.Header.Get("Authorization") != "Bearer new-session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	req, _ := http.NewRequest("PUT", server.URL+"/virtual_machines/1", strings.NewReader(`{"cores":2}`))
+	resp, err := c.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if c.session != "new-session" {
+		t.Fatalf("expected the new session to be kept, got %q", c.session)
+	}
+	if len(bodies) != 2 || bodies[1] != `{"cores":2}` {
+		t.Fatalf("expected the body to be sent again on the retry, got %q", bodies)
+	}
+}
+
+func TestClientRefreshesExpiredSessionOnce(t *testing.T) {
+	var mu sync.Mutex
+	sessions := 0
+
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/session" {
+			mu.Lock()
+			sessions++
+			mu.Unlock()
+			w.Write([]byte("fresh-session"))
+			return
+		}
+
+		// The session the client started with has expired
+		if r.Header.Get("Authorization") != "Bearer fresh-session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	c.session = "expired-session"
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+			resp, err := c.do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+		}()
+	}
+	wg.Wait()
+
+	if sessions != 1 {
+		t.Fatalf("expected requests expiring together to share one new session, got %d", sessions)
+	}
+}
+
+func TestClientApiKeyNotRefreshed(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/session" {
+			t.Error("API keys shouldn't ask for a new session")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	defer server.Close()
+
+	c.apiKeyAuth = true
+
+	req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+	if _, err := c.do(req); err == nil {
+		t.Fatal("expected a 401 error")
+	}
+}
+
+// countingConn
+// Lets a test know when the client has really closed its connection
+type countingConn struct {
+	net.Conn
+	open *int32
+	once sync.Once
+}
+
+func (c *countingConn) Close() error {
+	c.once.Do(func() { atomic.AddInt32(c.open, -1) })
+	return c.Conn.Close()
+}
+
+func TestNewSessionLeavesNoConnectionsOpen(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusForbidden} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+			w.Write([]byte("new-session"))
+		}))
+
+		var open int32
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				conn, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+				atomic.AddInt32(&open, 1)
+				return &countingConn{Conn: conn, open: &open}, nil
+			},
+		}
+
+		c := &client{
+			user:     "user",
+			password: "password",
+			authUrl:  server.URL + "/session",
+		}
+		c.httpInit.Do(func() {})
+		c.http = &http.Client{Transport: transport}
+
+		err := c.newSession()
+		if status == http.StatusOK && err != nil {
+			t.Fatal(err)
+		}
+		if status != http.StatusOK && err == nil {
+			t.Fatalf("expected HTTP Status %d to fail", status)
+		}
+
+		// Only connections whose body was closed go back to the pool to be closed here
+		transport.CloseIdleConnections()
+		if n := atomic.LoadInt32(&open); n != 0 {
+			t.Errorf("HTTP Status %d left %d connections open", status, n)
+		}
+
+		server.Close()
+	}
+}
+
+func TestBigvErrorFields(t *testing.T) {
+	err := bigvError(http.StatusUnprocessableEntity, []byte(`{"name":"is already taken","memory":["is too small","must be a multiple of 1024"]}`))
+
+	apiErr, ok := err.(*BigVAPIError)
+	if !ok {
+		t.Fatalf("expected a BigVAPIError, got %T: %s", err, err)
+	}
+	if apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("expected HTTP Status 422, got %d", apiErr.StatusCode)
+	}
+
+	expected := "Bigv returned HTTP Status 422, rejecting:\n" +
+		"  memory: is too small\n" +
+		"  memory: must be a multiple of 1024\n" +
+		"  name: is already taken"
+	if apiErr.Error() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, apiErr.Error())
+	}
+}
+
+func TestBigvErrorFallsBackToBody(t *testing.T) {
+	cases := []struct {
+		status int
+		body   string
+	}{
+		{http.StatusInternalServerError, `{"name":"is already taken"}`},
+		{http.StatusUnprocessableEntity, `not json`},
+		{http.StatusUnprocessableEntity, `{"name":{"nested":true}}`},
+	}
+
+	for _, c := range cases {
+		err := bigvError(c.status, []byte(c.body))
+		if _, ok := err.(*BigVAPIError); ok {
+			t.Errorf("HTTP Status %d with %s: expected a plain error", c.status, c.body)
+		}
+		if !strings.Contains(err.Error(), c.body) {
+			t.Errorf("HTTP Status %d: expected the body in %q", c.status, err)
+		}
+	}
+}
+
+func TestClientReturnsBigVAPIError(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"cores":"must be between 1 and 16"}`))
+	})
+	defer server.Close()
+
+	_, err := c.doJson("POST", server.URL+"/virtual_machines", map[string]int{"cores": 32}, nil)
+	apiErr, ok := err.(*BigVAPIError)
+	if !ok {
+		t.Fatalf("expected a BigVAPIError, got %T: %s", err, err)
+	}
+	if len(apiErr.Fields["cores"]) != 1 {
+		t.Fatalf("expected one error for cores, got %v", apiErr.Fields)
+	}
+}
+
+func TestUnavailableBackoff(t *testing.T) {
+	expected := []time.Duration{
+		2 * time.Second,
+		4 * time.Second,
+		8 * time.Second,
+		16 * time.Second,
+		32 * time.Second,
+		time.Minute,
+		time.Minute,
+		time.Minute,
+	}
+
+	for i, wait := range expected {
+		if got := unavailableBackoff(i + 1); got != wait {
+			t.Errorf("attempt %d: expected %s, got %s", i+1, wait, got)
+		}
+	}
+
+	if got := unavailableBackoff(100); got != time.Minute {
+		t.Errorf("expected a minute for a late attempt, got %s", got)
+	}
+}
+
+func TestClientRetries503(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	defer server.Close()
+
+	c.max503Retries = 1
+
+	req, _ := http.NewRequest("PUT", server.URL+"/virtual_machines/1", strings.NewReader(`{}`))
+	resp, err := c.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if requests != 2 {
+		t.Fatalf("expected the 503 to be retried once, got %d requests", requests)
+	}
+}
+
+func TestClient503CreatesNotRetried(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	c.max503Retries = 5
+	c.maintenanceRetryAttempts = 5
+
+	// bigv may have acted on it before failing, so a retry could make a second VM
+	req, _ := http.NewRequest("POST", server.URL+"/virtual_machines", strings.NewReader(`{}`))
+	if _, err := c.do(req); err == nil {
+		t.Fatal("expected the 503 to be returned")
+	}
+	if requests != 1 {
+		t.Fatalf("expected creates not to be retried, got %d requests", requests)
+	}
+}
+
+func TestClientRetriesShareOneCount(t *testing.T) {
+	statuses := []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusBadGateway}
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if requests < len(statuses) {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(statuses[requests])
+		}
+		requests++
+	})
+	defer server.Close()
+
+	c.maxRetries = 2
+	c.maintenanceRetryAttempts = 2
+	c.retryBackoffMultiplier = 0
+
+	// The 502, 503 then 502 is three retries, one more than either limit allows
+	req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+	if _, err := c.do(req); err == nil {
+		t.Fatal("expected the retries to run out")
+	}
+	if requests != 3 {
+		t.Fatalf("expected 3 requests, got %d", requests)
+	}
+}
+
+func TestClient503RetriesRunOut(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("down for maintenance"))
+	})
+	defer server.Close()
+
+	c.max503Retries = 1
+
+	req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+	_, err := c.do(req)
+	if err == nil {
+		t.Fatal("expected an error once the retries ran out")
+	}
+	if requests != 2 {
+		t.Fatalf("expected 2 requests, got %d", requests)
+	}
+
+	for _, part := range []string{"after 1 retries", "over 2s", "down for maintenance"} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("expected %q in %q", part, err)
+		}
+	}
+}
+
+func TestRetryableStatus(t *testing.T) {
+	cases := []struct {
+		method    string
+		status    int
+		retryable bool
+	}{
+		{"GET", http.StatusInternalServerError, true},
+		{"PUT", http.StatusBadGateway, true},
+		{"DELETE", http.StatusGatewayTimeout, true},
+		{"POST", http.StatusInternalServerError, false},
+		{"GET", http.StatusServiceUnavailable, true},
+		{"POST", http.StatusServiceUnavailable, false},
+		{"GET", http.StatusNotFound, false},
+		{"GET", http.StatusUnprocessableEntity, false},
+	}
+
+	for _, c := range cases {
+		if got := retryableStatus(c.method, c.status); got != c.retryable {
+			t.Errorf("%s with HTTP Status %d: expected retryable %t", c.method, c.status, c.retryable)
+		}
+	}
+}
+
+func TestClientRetriesMaintenance(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	defer server.Close()
+
+	c.maintenanceRetryAttempts = 3
+
+	req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+	resp, err := c.do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if requests != 2 {
+		t.Fatalf("expected the maintenance 503 to be retried once, got %d requests", requests)
+	}
+}
+
+func TestClientMaintenanceRetriesRunOut(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	c.maintenanceRetryAttempts = 3
+	// Retry-After is followed instead, so these don't apply
+	c.max503Retries = 10
+
+	req, _ := http.NewRequest("DELETE", server.URL+"/virtual_machines/1", nil)
+	_, err := c.do(req)
+	if err == nil || !strings.Contains(err.Error(), "after 3 retries") {
+		t.Fatalf("expected the retries to run out after 3, got %v", err)
+	}
+	if requests != 4 {
+		t.Fatalf("expected maintenance_retry_attempts of 3 to make 4 requests, got %d", requests)
+	}
+}
+
+func TestMaintenanceWait(t *testing.T) {
+	c := &client{maintenanceRetryMaxWait: 5 * time.Minute}
+
+	cases := []struct {
+		retryAfter string
+		wait       time.Duration
+	}{
+		{"120", 2 * time.Minute},
+		{"3600", 5 * time.Minute},
+		{"-5", 0},
+		{"soon", 0},
+		{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0},
+		{time.Now().Add(time.Hour).UTC().Format(http.TimeFormat), 5 * time.Minute},
+	}
+
+	for _, tc := range cases {
+		if wait := c.maintenanceWait(tc.retryAfter); wait != tc.wait {
+			t.Errorf("Retry-After %s: expected %s, got %s", tc.retryAfter, tc.wait, wait)
+		}
+	}
+}
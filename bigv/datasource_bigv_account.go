@@ -0,0 +1,48 @@
+package bigv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvAccount() *schema.Resource {
+	s := accountSchema()
+	for _, attr := range s {
+		attr.Optional = false
+		attr.Default = nil
+		attr.ValidateFunc = nil
+		attr.Computed = true
+	}
+	s["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceBigvAccountRead,
+
+		Schema: s,
+	}
+}
+
+// dataSourceBigvAccountRead
+// The provider's own account, which any user on it can read
+func dataSourceBigvAccountRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	account := &bigvAccount{}
+	if _, err := bigvClient.doJson("GET", bigvAccountUrl(bigvClient, bigvClient.account), nil, account); err != nil {
+		return err
+	}
+
+	if account.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for account %s", bigvClient.account)
+	}
+
+	d.SetId(strconv.Itoa(account.Id))
+	accountToResource(d, account)
+
+	return nil
+}
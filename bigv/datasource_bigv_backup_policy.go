@@ -0,0 +1,81 @@
+package bigv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceBigvBackupPolicy() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvBackupPolicyRead,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"group": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "default",
+			},
+			"frequency": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice(backupFrequencies, false),
+				Description:  "Which of the VM's policies, for VMs with more than one",
+			},
+			"retention_count": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"start_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"next_backup_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceBigvBackupPolicyRead
+// The VM's policy, which has to be the only one unless frequency picks it out
+func dataSourceBigvBackupPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var policies []bigvBackupPolicy
+	if _, err := bigvClient.doJson("GET", bigvBackupPoliciesUrl(d, bigvClient), nil, &policies); err != nil {
+		return err
+	}
+
+	frequency := d.Get("frequency").(string)
+
+	var found []bigvBackupPolicy
+	for _, policy := range policies {
+		if frequency == "" || policy.Frequency == frequency {
+			found = append(found, policy)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return fmt.Errorf("VM %s has no matching backup policy", d.Get("vm_id"))
+	case 1:
+		d.SetId(strconv.Itoa(found[0].Id))
+		backupPolicyToResource(d, &found[0])
+		return nil
+	default:
+		return fmt.Errorf("VM %s has %d backup policies, set frequency to pick one", d.Get("vm_id"), len(found))
+	}
+}
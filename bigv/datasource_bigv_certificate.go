@@ -0,0 +1,96 @@
+package bigv
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvCertificate() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvCertificateRead,
+
+		Schema: map[string]*schema.Schema{
+			"fingerprint": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"domain"},
+			},
+			"domain": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"fingerprint"},
+				Description:   "Any of the names the certificate is for",
+			},
+			"domains": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"auto_renew": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"certificate_pem": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"private_key_pem": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"expiry": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceBigvCertificateRead
+// Finds a certificate by fingerprint, or the first for a domain
+func dataSourceBigvCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	fingerprint := d.Get("fingerprint").(string)
+	domain := d.Get("domain").(string)
+	if fingerprint == "" && domain == "" {
+		return errors.New("One of fingerprint or domain must be set")
+	}
+
+	var certs []bigvCertificate
+	if _, err := bigvClient.doJson("GET", bigvCertificatesUrl(bigvClient), nil, &certs); err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		if certificateMatches(cert, fingerprint, domain) {
+			d.SetId(strconv.Itoa(cert.Id))
+			certificateToResource(d, &cert)
+			return nil
+		}
+	}
+
+	if fingerprint != "" {
+		return fmt.Errorf("No certificate with fingerprint %s", fingerprint)
+	}
+	return fmt.Errorf("No certificate for %s", domain)
+}
+
+func certificateMatches(cert bigvCertificate, fingerprint, domain string) bool {
+	if fingerprint != "" {
+		return cert.Fingerprint == fingerprint
+	}
+
+	for _, name := range cert.Domains {
+		if name == domain {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,166 @@
+package bigv
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvDistribution struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Deprecated  bool   `json:"deprecated,omitempty"`
+}
+
+func dataSourceBigvDistributions() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvDistributionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"distributions": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"deprecated": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"names": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceBigvDistribution() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvDistributionRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"exists": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"recommended": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether it exists and isn't deprecated",
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"deprecated": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceBigvDistributionsRead(d *schema.ResourceData, meta interface{}) error {
+	distributions, err := readBigvDistributions(meta.(*client))
+	if err != nil {
+		return err
+	}
+
+	list := make([]interface{}, len(distributions))
+	names := make([]string, len(distributions))
+	for i, distribution := range distributions {
+		list[i] = map[string]interface{}{
+			"name":        distribution.Name,
+			"description": distribution.Description,
+			"deprecated":  distribution.Deprecated,
+		}
+		names[i] = distribution.Name
+	}
+
+	// Changes whenever the distributions do
+	d.SetId(strings.Join(names, ","))
+	d.Set("distributions", list)
+	d.Set("names", names)
+
+	return nil
+}
+
+// dataSourceBigvDistributionRead
+// Unlike bigv_zone a missing distribution isn't an error, it just doesn't exist.
+// That lets configs pick another when one is withdrawn.
+func dataSourceBigvDistributionRead(d *schema.ResourceData, meta interface{}) error {
+	distributions, err := readBigvDistributions(meta.(*client))
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	d.SetId(name)
+
+	distribution, ok := findBigvDistribution(distributions, name)
+	d.Set("exists", ok)
+	d.Set("recommended", ok && !distribution.Deprecated)
+	d.Set("description", distribution.Description)
+	d.Set("deprecated", distribution.Deprecated)
+
+	return nil
+}
+
+func findBigvDistribution(distributions []bigvDistribution, name string) (bigvDistribution, bool) {
+	for _, distribution := range distributions {
+		if distribution.Name == name {
+			return distribution, true
+		}
+	}
+
+	return bigvDistribution{}, false
+}
+
+func readBigvDistributions(bigvClient *client) ([]bigvDistribution, error) {
+	url := fmt.Sprintf("%s/distributions", bigvClient.apiUri())
+
+	log.Printf("[DEBUG] Distributions Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Read Distributions Bad HTTP status from bigv: %d", resp.StatusCode)
+	}
+
+	var distributions []bigvDistribution
+	if err := json.NewDecoder(resp.Body).Decode(&distributions); err != nil {
+		return nil, err
+	}
+
+	return distributions, nil
+}
@@ -0,0 +1,52 @@
+package bigv
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvDnsZone() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvDnsZoneRead,
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"ttl": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"contact_email": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"nameservers": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceBigvDnsZoneRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	domain := d.Get("domain").(string)
+
+	zone, err := readBigvDnsZone(bigvClient, domain)
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return fmt.Errorf("bigv doesn't host a DNS zone for %s", domain)
+	}
+
+	d.SetId(domain)
+	dnsZoneToResource(d, zone)
+
+	return nil
+}
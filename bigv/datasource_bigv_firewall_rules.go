@@ -0,0 +1,84 @@
+package bigv
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvFirewallRules() *schema.Resource {
+	// The same attributes, but all read from bigv
+	rule := firewallRuleSchema()
+	for _, s := range rule {
+		s.Optional = false
+		s.Required = false
+		s.Default = nil
+		s.ValidateFunc = nil
+		s.Computed = true
+	}
+	rule["id"] = &schema.Schema{
+		Type:     schema.TypeInt,
+		Computed: true,
+	}
+
+	return &schema.Resource{
+		Read: dataSourceBigvFirewallRulesRead,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"group_id"},
+			},
+			"group_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"vm_id"},
+			},
+			"rules": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: rule,
+				},
+			},
+		},
+	}
+}
+
+// dataSourceBigvFirewallRulesRead
+// Every rule on the VM or group, however they were made
+func dataSourceBigvFirewallRulesRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url, err := bigvFirewallRulesUrl(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	var rules []bigvFirewallRule
+	if _, err := bigvClient.doJson("GET", url, nil, &rules); err != nil {
+		return err
+	}
+
+	list := make([]interface{}, len(rules))
+	for i, rule := range rules {
+		list[i] = map[string]interface{}{
+			"id":               rule.Id,
+			"direction":        rule.Direction,
+			"protocol":         rule.Protocol,
+			"port_range":       rule.PortRange,
+			"source_cidr":      rule.SourceCidr,
+			"destination_cidr": rule.DestinationCidr,
+			"action":           rule.Action,
+			"priority":         rule.Priority,
+		}
+	}
+
+	if vmId := d.Get("vm_id").(string); vmId != "" {
+		d.SetId("vm/" + vmId)
+	} else {
+		d.SetId("group/" + d.Get("group_id").(string))
+	}
+	d.Set("rules", list)
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package bigv
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvGroup() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvGroupRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"account": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The account the group is in. Defaults to the provider's",
+			},
+			"group_billing_code": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vm_count": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceBigvGroupRead
+// Looks the group up by name, mostly to find its id
+func dataSourceBigvGroupRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	account := d.Get("account").(string)
+	if account == "" {
+		account = bigvClient.account
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s?view=overview",
+		bigvClient.apiUri(),
+		account,
+		d.Get("name"),
+	)
+
+	log.Printf("[DEBUG] Group Data Source Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Read Group Bad HTTP status from bigv: %d", resp.StatusCode)
+	}
+
+	group := &bigvGroup{}
+	if err := json.NewDecoder(resp.Body).Decode(group); err != nil {
+		return err
+	}
+
+	if group.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for group %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+	groupToResource(d, group)
+	d.Set("account", account)
+
+	return nil
+}
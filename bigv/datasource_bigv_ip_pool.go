@@ -0,0 +1,64 @@
+package bigv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvIpPool() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvIpPoolRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The group the pool is in, if it isn't account wide",
+			},
+			"cidr": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"total_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"used_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceBigvIpPoolRead
+// Looks the pool up by name, e.g. to check how full it is
+func dataSourceBigvIpPoolRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var pools []bigvIpPool
+	if _, err := bigvClient.doJson("GET", bigvIpPoolsUrl(d, bigvClient), nil, &pools); err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	for _, pool := range pools {
+		if pool.Name == name {
+			d.SetId(strconv.Itoa(pool.Id))
+			ipPoolToResource(d, &pool)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("No ip pool named %s", name)
+}
@@ -0,0 +1,98 @@
+package bigv
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceBigvMonitoringAlert() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvMonitoringAlertRead,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"metric": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(alertMetrics, false),
+				Description:  "Only list the alerts on this metric",
+			},
+			"alerts": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"metric": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"threshold": &schema.Schema{
+							Type:     schema.TypeFloat,
+							Computed: true,
+						},
+						"comparison": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"duration_seconds": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"notification_email": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"enabled": &schema.Schema{
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceBigvMonitoringAlertRead
+// Lists the VM's alerts, however they were made
+func dataSourceBigvMonitoringAlertRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vmId := d.Get("vm_id").(string)
+
+	var alerts []bigvMonitoringAlert
+	if _, err := bigvClient.doJson("GET", bigvMonitoringAlertsUrl(bigvClient, vmId), nil, &alerts); err != nil {
+		return err
+	}
+
+	metric := d.Get("metric").(string)
+
+	list := make([]interface{}, 0, len(alerts))
+	for _, alert := range alerts {
+		if metric != "" && alert.Metric != metric {
+			continue
+		}
+
+		list = append(list, map[string]interface{}{
+			"id":                 alert.Id,
+			"metric":             alert.Metric,
+			"threshold":          alert.Threshold,
+			"comparison":         alert.Comparison,
+			"duration_seconds":   alert.DurationSeconds,
+			"notification_email": alert.NotificationEmail,
+			"enabled":            alert.Enabled,
+		})
+	}
+
+	d.SetId(vmId)
+	d.Set("alerts", list)
+
+	return nil
+}
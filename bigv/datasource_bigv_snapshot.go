@@ -0,0 +1,64 @@
+package bigv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvSnapshotRead,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"group": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "default",
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"expiry": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// dataSourceBigvSnapshotRead
+// Finds the VM's snapshot with the label, e.g. to restore from
+func dataSourceBigvSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var snapshots []bigvSnapshot
+	if _, err := bigvClient.doJson("GET", bigvSnapshotsUrl(d, bigvClient), nil, &snapshots); err != nil {
+		return err
+	}
+
+	label := d.Get("label").(string)
+	for _, snapshot := range snapshots {
+		if snapshot.Label == label {
+			d.SetId(strconv.Itoa(snapshot.Id))
+			snapshotToResource(d, &snapshot)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("VM %s has no snapshot labelled %s", d.Get("vm_id"), label)
+}
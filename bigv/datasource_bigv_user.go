@@ -0,0 +1,56 @@
+package bigv
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvUser() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvUserRead,
+
+		Schema: map[string]*schema.Schema{
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"admin": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"groups": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// dataSourceBigvUserRead
+// Looks the user up by username in the provider's account
+func dataSourceBigvUserRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var users []bigvUser
+	if _, err := bigvClient.doJson("GET", bigvUsersUrl(bigvClient), nil, &users); err != nil {
+		return err
+	}
+
+	username := d.Get("username").(string)
+	for _, user := range users {
+		if user.Username == username {
+			d.SetId(strconv.Itoa(user.Id))
+			userToResource(d, &user)
+			return nil
+		}
+	}
+
+	return fmt.Errorf("No user %s in bigv account %s", username, bigvClient.account)
+}
@@ -0,0 +1,212 @@
+package bigv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceBigvVM() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvVMRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"group": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "default",
+			},
+			"account": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The account the VM is in. Defaults to the provider's",
+			},
+			"group_id": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"resource_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv4_ptr": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"hostname": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"mac_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fqdn": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"state": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"billing_code": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv4": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ipv6": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cores": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"os": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"power_on": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"reboot": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"kernel_cmdline": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"image_installer": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"disc": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"storage_grade": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provisioned_iops": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"network_interface": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vlan_num": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ipv4": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv6": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mac": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// dataSourceBigvVMRead
+// Reads the VM just as the resource does, it's only found by name rather than id
+func dataSourceBigvVMRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	account := d.Get("account").(string)
+	if account == "" {
+		account = bigvClient.account
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s?view=overview",
+		bigvClient.apiUri(),
+		account,
+		d.Get("group"),
+		d.Get("name"),
+	)
+
+	log.Printf("[DEBUG] VM Data Source Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Read VM Bad HTTP status from bigv: %d", resp.StatusCode)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if err := resourceFromJson(d, body); err != nil {
+		return err
+	}
+
+	d.Set("account", account)
+	setResourcePath(d, account)
+
+	if err := readBigvVMGroup(d, bigvClient, account); err != nil {
+		return err
+	}
+
+	return readBigvIPv4Ptr(d, bigvClient)
+}
@@ -0,0 +1,132 @@
+package bigv
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvZone struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+func dataSourceBigvZones() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvZonesRead,
+
+		Schema: map[string]*schema.Schema{
+			"zones": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"names": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceBigvZone() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceBigvZoneRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+}
+
+func dataSourceBigvZonesRead(d *schema.ResourceData, meta interface{}) error {
+	zones, err := readBigvZones(meta.(*client))
+	if err != nil {
+		return err
+	}
+
+	list := make([]interface{}, len(zones))
+	names := make([]string, len(zones))
+	for i, zone := range zones {
+		list[i] = map[string]interface{}{
+			"id":   zone.Id,
+			"name": zone.Name,
+		}
+		names[i] = zone.Name
+	}
+
+	// Changes whenever the zones do
+	d.SetId(strings.Join(names, ","))
+	d.Set("zones", list)
+	d.Set("names", names)
+
+	return nil
+}
+
+// dataSourceBigvZoneRead
+// Fails if there's no such zone, so typos are caught before any VMs are made
+func dataSourceBigvZoneRead(d *schema.ResourceData, meta interface{}) error {
+	zones, err := readBigvZones(meta.(*client))
+	if err != nil {
+		return err
+	}
+
+	name := d.Get("name").(string)
+	for _, zone := range zones {
+		if zone.Name == name {
+			d.SetId(strconv.Itoa(zone.Id))
+			return nil
+		}
+	}
+
+	return fmt.Errorf("No bigv zone named %s", name)
+}
+
+func readBigvZones(bigvClient *client) ([]bigvZone, error) {
+	url := fmt.Sprintf("%s/zones", bigvClient.apiUri())
+
+	log.Printf("[DEBUG] Zones Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Read Zones Bad HTTP status from bigv: %d", resp.StatusCode)
+	}
+
+	var zones []bigvZone
+	if err := json.NewDecoder(resp.Body).Decode(&zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
@@ -0,0 +1,195 @@
+package bigv
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// The heredoc marker wrapping any user supplied firstboot_script
+const firstbootMarker = "BIGV_FIRSTBOOT_SCRIPT"
+
+// firstbootScript
+// bigv imaging doesn't know about everything we'd like to configure,
+// so anything it can't do gets done by shell run ahead of the user's firstboot_script.
+// With nothing to configure the user's script is sent untouched.
+func firstbootScript(d *schema.ResourceData) string {
+	script := d.Get("firstboot_script").(string)
+
+	var snippets []string
+	snippets = append(snippets, dnsServersFirstboot(d)...)
+	snippets = append(snippets, ntpServersFirstboot(d)...)
+	snippets = append(snippets, localeFirstboot(d)...)
+
+	if len(snippets) == 0 {
+		return script
+	}
+
+	lines := append([]string{"#!/bin/sh"}, snippets...)
+
+	// Run the user's script as it was written, shebang and all
+	if script != "" {
+		lines = append(lines,
+			fmt.Sprintf("cat > /root/firstboot_script <<'%s'", firstbootMarker),
+			strings.TrimRight(script, "\n"),
+			firstbootMarker,
+			"chmod 700 /root/firstboot_script",
+			"/root/firstboot_script",
+		)
+	}
+
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func dnsServersFirstboot(d *schema.ResourceData) []string {
+	servers := d.Get("dns_servers").([]interface{})
+	if len(servers) == 0 {
+		return nil
+	}
+
+	lines := []string{": > /etc/resolv.conf"}
+	for _, s := range servers {
+		lines = append(lines, fmt.Sprintf("echo 'nameserver %s' >> /etc/resolv.conf", s))
+	}
+
+	return lines
+}
+
+// ntpServersFirstboot
+// Replaces the servers in whichever of ntpd or chrony the distribution uses
+func ntpServersFirstboot(d *schema.ResourceData) []string {
+	servers := d.Get("ntp_servers").([]interface{})
+	if len(servers) == 0 {
+		return nil
+	}
+
+	lines := []string{
+		"for conf in /etc/ntp.conf /etc/chrony.conf /etc/chrony/chrony.conf; do",
+		"  [ -f \"$conf\" ] || continue",
+		"  sed -i '/^\\(server\\|pool\\) /d' \"$conf\"",
+	}
+	for _, s := range servers {
+		lines = append(lines, fmt.Sprintf("  echo 'server %s iburst' >> \"$conf\"", s))
+	}
+	lines = append(lines, "done")
+
+	return lines
+}
+
+// bigv images come with this locale already
+const defaultLocale = "en_GB.UTF-8"
+
+func localeFirstboot(d *schema.ResourceData) []string {
+	locale := d.Get("locale").(string)
+	if locale == "" || locale == defaultLocale {
+		return nil
+	}
+
+	return []string{
+		"if [ -f /etc/locale.gen ]; then",
+		fmt.Sprintf("  sed -i 's/^# *\\(%s\\)/\\1/' /etc/locale.gen", locale),
+		"  locale-gen",
+		"else",
+		fmt.Sprintf("  locale-gen '%s'", locale),
+		"fi",
+		fmt.Sprintf("update-locale LANG='%s'", locale),
+	}
+}
+
+var localeRegexp = regexp.MustCompile(`^[a-z]{2}_[A-Z]{2}([.@][A-Za-z0-9@._-]+)?$`)
+
+// validateLocale
+// e.g. en_GB.UTF-8 or de_DE@euro
+func validateLocale(v interface{}, k string) (ws []string, es []error) {
+	if !localeRegexp.MatchString(v.(string)) {
+		es = append(es, fmt.Errorf("%s must be a locale such as en_GB.UTF-8, got: %s", k, v))
+	}
+	return
+}
+
+// suppressListOrderDiff
+// For lists where only the members matter, not their order
+func suppressListOrderDiff(k, old, new string, d *schema.ResourceData) bool {
+	// k is an element of the list, we want the list itself
+	list := k[:strings.LastIndex(k, ".")]
+	o, n := d.GetChange(list)
+
+	return sameStrings(o.([]interface{}), n.([]interface{}))
+}
+
+func sameStrings(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	as := make([]string, len(a))
+	bs := make([]string, len(b))
+	for i := range a {
+		as[i] = a[i].(string)
+		bs[i] = b[i].(string)
+	}
+	sort.Strings(as)
+	sort.Strings(bs)
+
+	for i := range as {
+		if as[i] != bs[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+var hostnameRegexp = regexp.MustCompile(`^([a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?\.)*[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+// validateHostOrIP
+// An IP address or a DNS hostname
+func validateHostOrIP(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if net.ParseIP(value) == nil && !hostnameRegexp.MatchString(value) {
+		es = append(es, fmt.Errorf("%s must be a hostname or IP address, got: %s", k, value))
+	}
+	return
+}
+
+// validateIP
+// Any IPv4 or IPv6 address
+func validateIP(v interface{}, k string) (ws []string, es []error) {
+	if net.ParseIP(v.(string)) == nil {
+		es = append(es, fmt.Errorf("%s must be an IP address, got: %s", k, v))
+	}
+	return
+}
+
+// validateIPv4
+// An IPv4 address, or nothing to let bigv pick
+func validateIPv4(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if value == "" {
+		return
+	}
+
+	if ip := net.ParseIP(value); ip == nil || ip.To4() == nil {
+		es = append(es, fmt.Errorf("%s must be an IPv4 address, got: %s", k, value))
+	}
+	return
+}
+
+// validateIPv6
+// An IPv6 address, or nothing to let bigv pick
+func validateIPv6(v interface{}, k string) (ws []string, es []error) {
+	value := v.(string)
+	if value == "" {
+		return
+	}
+
+	// To4 also accepts IPv4 mapped IPv6 addresses, which bigv won't allocate
+	if ip := net.ParseIP(value); ip == nil || ip.To4() != nil {
+		es = append(es, fmt.Errorf("%s must be an IPv6 address, got: %s", k, value))
+	}
+	return
+}
@@ -0,0 +1,208 @@
+package bigv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestValidateLocale(t *testing.T) {
+	valid := []string{"en_GB.UTF-8", "en_US.UTF-8", "de_DE@euro", "fr_FR", "pt_BR.ISO-8859-1"}
+	for _, locale := range valid {
+		if _, es := validateLocale(locale, "locale"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", locale, es)
+		}
+	}
+
+	invalid := []string{"", "en", "EN_gb.UTF-8", "en-GB.UTF-8", "en_GB.", "en_GB UTF-8"}
+	for _, locale := range invalid {
+		if _, es := validateLocale(locale, "locale"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", locale)
+		}
+	}
+}
+
+func TestLocaleFirstboot(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{})
+	if script := firstbootScript(d); script != "" {
+		t.Fatalf("expected nothing to do for the default locale, got %q", script)
+	}
+
+	d = testVMResourceData(t, map[string]interface{}{
+		"locale":           "en_US.UTF-8",
+		"firstboot_script": "#!/bin/bash\necho hello\n",
+	})
+
+	script := firstbootScript(d)
+	if !strings.HasPrefix(script, "#!/bin/sh\n") {
+		t.Errorf("expected a shell script, got %q", script)
+	}
+	if !strings.Contains(script, "update-locale LANG='en_US.UTF-8'") {
+		t.Errorf("expected the locale to be set, got %q", script)
+	}
+
+	// The user's script runs after, as written
+	if !strings.Contains(script, "#!/bin/bash\necho hello\n"+firstbootMarker) {
+		t.Errorf("expected the user's script to be kept, got %q", script)
+	}
+}
+
+func TestValidateIP(t *testing.T) {
+	valid := []string{"192.0.2.53", "2001:db8::53", "::1", "10.0.0.1"}
+	for _, ip := range valid {
+		if _, es := validateIP(ip, "dns_servers.0"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", ip, es)
+		}
+	}
+
+	invalid := []string{"", "192.0.2", "192.0.2.256", "dns.example.com", "2001:db8::53::1", "192.0.2.53/32", " 192.0.2.53"}
+	for _, ip := range invalid {
+		if _, es := validateIP(ip, "dns_servers.0"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", ip)
+		}
+	}
+}
+
+func TestDnsServersFirstboot(t *testing.T) {
+	cases := []struct {
+		servers  []interface{}
+		expected string
+	}{
+		{nil, ""},
+		{
+			[]interface{}{"192.0.2.53"},
+			"#!/bin/sh\n" +
+				": > /etc/resolv.conf\n" +
+				"echo 'nameserver 192.0.2.53' >> /etc/resolv.conf\n",
+		},
+		{
+			[]interface{}{"192.0.2.53", "2001:db8::53"},
+			"#!/bin/sh\n" +
+				": > /etc/resolv.conf\n" +
+				"echo 'nameserver 192.0.2.53' >> /etc/resolv.conf\n" +
+				"echo 'nameserver 2001:db8::53' >> /etc/resolv.conf\n",
+		},
+	}
+
+	for _, tc := range cases {
+		d := testVMResourceData(t, map[string]interface{}{"dns_servers": tc.servers})
+		if script := firstbootScript(d); script != tc.expected {
+			t.Errorf("%v: expected:\n%s\ngot:\n%s", tc.servers, tc.expected, script)
+		}
+	}
+}
+
+func TestDnsServersLimit(t *testing.T) {
+	servers := []interface{}{"192.0.2.1", "192.0.2.2", "192.0.2.3", "192.0.2.4"}
+	for _, n := range []int{3, 4} {
+		rc, err := config.NewRawConfig(map[string]interface{}{"name": "web", "dns_servers": servers[:n]})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, es := resourceBigvVM().Validate(terraform.NewResourceConfig(rc))
+		if n <= 3 && len(es) > 0 {
+			t.Errorf("%d servers: expected them to be valid, got %s", n, es)
+		}
+		if n > 3 && len(es) == 0 {
+			t.Errorf("%d servers: expected resolv.conf's limit of 3 to be enforced", n)
+		}
+	}
+}
+
+func TestValidateHostOrIP(t *testing.T) {
+	valid := []string{"192.0.2.123", "2001:db8::123", "ntp.example.com", "pool.ntp.org", "ntp1", "0.uk.pool.ntp.org"}
+	for _, host := range valid {
+		if _, es := validateHostOrIP(host, "ntp_servers.0"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", host, es)
+		}
+	}
+
+	invalid := []string{"", "-ntp.example.com", "ntp..example.com", "ntp.example.com.", "ntp example.com", "ntp_1.example.com", "ntp.example.com;reboot"}
+	for _, host := range invalid {
+		if _, es := validateHostOrIP(host, "ntp_servers.0"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", host)
+		}
+	}
+}
+
+func TestNtpServersFirstboot(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{
+		"ntp_servers": []interface{}{"ntp.example.com", "192.0.2.123"},
+	})
+
+	expected := "#!/bin/sh\n" +
+		"for conf in /etc/ntp.conf /etc/chrony.conf /etc/chrony/chrony.conf; do\n" +
+		"  [ -f \"$conf\" ] || continue\n" +
+		"  sed -i '/^\\(server\\|pool\\) /d' \"$conf\"\n" +
+		"  echo 'server ntp.example.com iburst' >> \"$conf\"\n" +
+		"  echo 'server 192.0.2.123 iburst' >> \"$conf\"\n" +
+		"done\n"
+	if script := firstbootScript(d); script != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, script)
+	}
+}
+
+func TestFirstbootSnippetsWithUserScript(t *testing.T) {
+	user := "#!/bin/bash\necho \"set up $(hostname)\"\n"
+
+	d := testVMResourceData(t, map[string]interface{}{"firstboot_script": user})
+	if script := firstbootScript(d); script != user {
+		t.Errorf("expected the user's script untouched with nothing else to do, got %q", script)
+	}
+
+	d = testVMResourceData(t, map[string]interface{}{
+		"dns_servers":      []interface{}{"192.0.2.53"},
+		"ntp_servers":      []interface{}{"ntp.example.com"},
+		"firstboot_script": user,
+	})
+	script := firstbootScript(d)
+
+	// dns first so the ntp servers resolve, then the user's script last, quoted so its $() isn't run early
+	dns := strings.Index(script, "nameserver 192.0.2.53")
+	ntp := strings.Index(script, "server ntp.example.com iburst")
+	heredoc := strings.Index(script, "cat > /root/firstboot_script <<'"+firstbootMarker+"'\n"+user+firstbootMarker+"\n")
+	run := strings.Index(script, "chmod 700 /root/firstboot_script\n/root/firstboot_script\n")
+
+	if dns < 0 || ntp < dns || heredoc < ntp || run < heredoc {
+		t.Errorf("expected dns, ntp, then the user's script written out and run, got:\n%s", script)
+	}
+	if !strings.HasSuffix(script, "/root/firstboot_script\n") {
+		t.Errorf("expected running the user's script to be the last thing, got:\n%s", script)
+	}
+}
+
+func TestValidateIPv4(t *testing.T) {
+	valid := []string{"", "192.0.2.10", "10.0.0.1", "255.255.255.255"}
+	for _, ip := range valid {
+		if _, es := validateIPv4(ip, "ipv4"); len(es) > 0 {
+			t.Errorf("%q: expected it to be valid, got %s", ip, es)
+		}
+	}
+
+	invalid := []string{"256.0.0.1", "not-an-ip", "192.0.2", "192.0.2.10/24", "2001:db8::10", " 192.0.2.10"}
+	for _, ip := range invalid {
+		if _, es := validateIPv4(ip, "ipv4"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", ip)
+		}
+	}
+}
+
+func TestValidateIPv6(t *testing.T) {
+	valid := []string{"", "2001:db8::10", "::1", "fe80::1", "2001:0db8:0000:0000:0000:0000:0000:0010"}
+	for _, ip := range valid {
+		if _, es := validateIPv6(ip, "ipv6"); len(es) > 0 {
+			t.Errorf("%q: expected it to be valid, got %s", ip, es)
+		}
+	}
+
+	// bigv never hands out IPv4 mapped addresses
+	invalid := []string{"192.0.2.10", "::ffff:192.0.2.10", "2001:db8::10::1", "2001:db8::g", "not-an-ip", "2001:db8::/64"}
+	for _, ip := range invalid {
+		if _, es := validateIPv6(ip, "ipv6"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", ip)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package bigv
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+const redacted = "********"
+
+// Json fields never to log the values of, in anything sent to or from bigv
+var sensitiveFields = map[string]bool{
+	"password":       true,
+	"root_password":  true,
+	"session":        true,
+	"2fa":            true,
+	"totp_secret":    true,
+	"api_key":        true,
+	"access_key":     true,
+	"secret_key":     true,
+	"private_key":    true,
+	"pre_shared_key": true,
+}
+
+// loggingTransport
+// Logs every request and response in full for debug_http, with credentials redacted.
+// secrets gives the client's current credentials, which are masked wherever they turn up,
+// since the session comes back from the auth server as plain text.
+type loggingTransport struct {
+	transport http.RoundTripper
+	secrets   func() []string
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	log.Printf("[DEBUG] HTTP request: %s %s\n%s\n%s", req.Method, req.URL, t.redactHeaders(req.Header), t.redact(body))
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		log.Printf("[DEBUG] HTTP request failed: %s", err)
+		return resp, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	log.Printf("[DEBUG] HTTP response: %s\n%s\n%s", resp.Status, t.redactHeaders(resp.Header), t.redact(respBody))
+
+	return resp, nil
+}
+
+// redactHeaders
+// Headers in a stable order, with the Authorization value hidden
+func (t *loggingTransport) redactHeaders(header http.Header) string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(header[name], ", ")
+		if http.CanonicalHeaderKey(name) == "Authorization" {
+			value = redacted
+		}
+		lines = append(lines, name+": "+t.redactSecrets(value))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (t *loggingTransport) redact(body []byte) string {
+	return t.redactSecrets(redactJson(body))
+}
+
+func (t *loggingTransport) redactSecrets(s string) string {
+	if t.secrets == nil {
+		return s
+	}
+
+	for _, secret := range t.secrets() {
+		if secret != "" {
+			s = strings.Replace(s, secret, redacted, -1)
+		}
+	}
+
+	return s
+}
+
+// redactJson
+// The body with any sensitive fields hidden, wherever they're nested.
+// Anything that isn't json is left as it is.
+func redactJson(body []byte) string {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return string(body)
+	}
+
+	redacted, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(redacted)
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, field := range v {
+			if sensitiveFields[k] {
+				v[k] = redacted
+			} else {
+				v[k] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+
+	return v
+}
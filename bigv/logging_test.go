@@ -0,0 +1,141 @@
+package bigv
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+// Every field bigv could send a credential in
+var testSensitiveFields = []string{
+	"password",
+	"root_password",
+	"session",
+	"2fa",
+	"totp_secret",
+	"api_key",
+	"access_key",
+	"secret_key",
+	"private_key",
+	"pre_shared_key",
+}
+
+// testCaptureLog
+// Everything logged while f runs
+func testCaptureLog(f func()) string {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	f()
+
+	return buf.String()
+}
+
+func TestRedactJson(t *testing.T) {
+	for _, field := range testSensitiveFields {
+		secret := "secret-" + field
+		body := fmt.Sprintf(`{"name":"web","%s":"%s","nested":[{"%s":"%s"}]}`, field, secret, field, secret)
+
+		redacted := redactJson([]byte(body))
+		if strings.Contains(redacted, secret) {
+			t.Errorf("%s: expected it redacted, got %s", field, redacted)
+		}
+		if !strings.Contains(redacted, `"name":"web"`) {
+			t.Errorf("%s: expected the other fields left alone, got %s", field, redacted)
+		}
+	}
+
+	if redacted := redactJson([]byte("not json")); redacted != "not json" {
+		t.Errorf("expected anything that isn't json to be left alone, got %s", redacted)
+	}
+}
+
+func TestLoggingTransportRedacts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The auth server sends the session back as plain text
+		w.Write([]byte("plain-session"))
+	}))
+	defer server.Close()
+
+	secrets := []string{"client-password", "plain-session", "123456", "JBSWY3DPEHPK3PXP"}
+	transport := &loggingTransport{
+		transport: http.DefaultTransport,
+		secrets:   func() []string { return secrets },
+	}
+
+	fields := []string{}
+	for _, field := range testSensitiveFields {
+		fields = append(fields, fmt.Sprintf(`"%s":"secret-%s"`, field, field))
+	}
+	body := "{" + strings.Join(fields, ",") + `,"note":"client-password"}`
+
+	logged := testCaptureLog(func() {
+		req, _ := http.NewRequest("POST", server.URL+"/session", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer api-key")
+		resp, err := transport.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+	})
+
+	for _, field := range testSensitiveFields {
+		if strings.Contains(logged, "secret-"+field) {
+			t.Errorf("expected %s to be redacted, got:\n%s", field, logged)
+		}
+	}
+	for _, secret := range append(secrets, "api-key") {
+		if strings.Contains(logged, secret) {
+			t.Errorf("expected %s to be redacted, got:\n%s", secret, logged)
+		}
+	}
+	if !strings.Contains(logged, "POST "+server.URL+"/session") {
+		t.Errorf("expected the request to be logged, got:\n%s", logged)
+	}
+}
+
+func TestClientSecretsRedacted(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	c.totp = "123456"
+	c.totpSecret = "JBSWY3DPEHPK3PXP"
+	c.debugHttp = true
+	c.initHttp()
+
+	secrets := c.http.Transport.(*loggingTransport).secrets()
+	for _, secret := range []string{"password", "session", "123456", "JBSWY3DPEHPK3PXP"} {
+		found := false
+		for _, s := range secrets {
+			found = found || s == secret
+		}
+		if !found {
+			t.Errorf("expected %s to be redacted, got %v", secret, secrets)
+		}
+	}
+}
+
+func TestRequestBodiesOnlyLoggedForDebugHttp(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	logged := testCaptureLog(func() {
+		if _, err := c.doJson("POST", server.URL+"/accounts/myaccount/api_keys", map[string]string{"label": "ci-runner"}, nil); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	if strings.Contains(logged, "ci-runner") {
+		t.Errorf("expected the body not to be logged without debug_http, got:\n%s", logged)
+	}
+}
@@ -1,12 +1,20 @@
 package bigv
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hashicorp/terraform/terraform"
 )
 
 func Provider() terraform.ResourceProvider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"account": &schema.Schema{
 				Type:        schema.TypeString,
@@ -16,31 +24,318 @@ func Provider() terraform.ResourceProvider {
 			},
 			"user": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("BIGV_USER", nil),
 				Description: "The bigv user name",
 			},
 			"password": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("BGIV_PASSWORD", nil),
 				Description: "The bigv password",
 			},
+			"api_key": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_API_KEY", ""),
+				Description: "A bigv API key, used instead of user and password",
+			},
+			"totp": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_TOTP", ""),
+				Description: "A two-factor authentication code, for accounts with it enabled",
+			},
+			"totp_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_TOTP_SECRET", ""),
+				Description: "The base32 two-factor authentication secret, to generate codes as needed",
+			},
+			"api_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_API_URL", bigvUri),
+				Description: "The bigv API, for staging environments or test servers",
+			},
+			"auth_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_AUTH_URL", bigvAuthUri),
+				Description: "The bigv authentication session endpoint",
+			},
+			"api_version": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("BIGV_API_VERSION", "v1"),
+				ValidateFunc: validateApiVersion,
+				Description:  "The bigv API version to use, e.g. v1",
+			},
+			"http_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      bigvTimeout,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Seconds before giving up on each bigv API request",
+			},
+			"max_concurrent_operations": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     10,
+				Description: "The maximum number of concurrent requests made to bigv",
+			},
+			"create_pipeline_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "serial",
+				ValidateFunc: validation.StringInSlice([]string{"serial", "parallel", "smart"}, false),
+				Description:  "How VM creates are sent to bigv: serial, parallel, or smart for up to max_concurrent_creates at once",
+			},
+			"max_concurrent_creates": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How many VM creates are sent to bigv at once in smart create_pipeline_mode",
+			},
+			"provision_poll_interval": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      vmCheckInterval,
+				ValidateFunc: validation.IntBetween(1, 60),
+				Description:  "Seconds between checks on VMs being provisioned",
+			},
+			"resize_poll_interval": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      vmCheckInterval,
+				ValidateFunc: validation.IntBetween(1, 60),
+				Description:  "Seconds between checks on VMs restarting after a resize",
+			},
+			"maintenance_retry_attempts": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     5,
+				Description: "How many times to retry requests while bigv is down for maintenance",
+			},
+			"maintenance_retry_max_wait": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     300,
+				Description: "The longest to wait in seconds before each maintenance retry",
+			},
+			"max_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many times to retry GET, PUT and DELETE requests bigv fails with a 5xx error",
+			},
+			"retry_backoff_multiplier": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      2.0,
+				ValidateFunc: validateBackoffMultiplier,
+				Description:  "How much longer to wait before each retry than the last, starting from 1 second",
+			},
+			"debug_http": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_DEBUG_HTTP", false),
+				Description: "Log every request to bigv and its response in full, with credentials redacted",
+			},
+			"default_group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("BIGV_GROUP", "default"),
+				Description: "The group for VMs that don't set their own",
+			},
+			"default_zone": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("BIGV_ZONE", "york"),
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+				Description:  "The zone for VMs that don't set their own",
+			},
+			"default_os": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "bookworm",
+				ValidateFunc: validateBigvOsName,
+				Description:  "The os for VMs that don't set their own",
+			},
+			"default_disc_size": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      defaultDiscSize,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "The size in MiB of VM discs that don't set their own",
+			},
+			"default_storage_grade": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultStorageGrade,
+				ValidateFunc: validation.StringInSlice(storageGrades, false),
+				Description:  "The storage grade of VM discs that don't set their own",
+			},
+			"max_503_retries": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "How many times to retry requests bigv fails with a 503 and no Retry-After, backing off up to a minute",
+			},
+			"validate_os": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Check VM os names against bigv's distributions when planning",
+			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"bigv_vm": resourceBigvVM(),
+			"bigv_vm":                        resourceBigvVM(),
+			"bigv_group":                     resourceBigvGroup(),
+			"bigv_ip":                        resourceBigvIp(),
+			"bigv_disc":                      resourceBigvDisc(),
+			"bigv_nic":                       resourceBigvNic(),
+			"bigv_dns_record":                resourceBigvDnsRecord(),
+			"bigv_reverse_dns":               resourceBigvReverseDns(),
+			"bigv_ssh_key":                   resourceBigvSshKey(),
+			"bigv_snapshot":                  resourceBigvSnapshot(),
+			"bigv_firewall_rule":             resourceBigvFirewallRule(),
+			"bigv_floating_ip":               resourceBigvFloatingIp(),
+			"bigv_floating_ip_attachment":    resourceBigvFloatingIpAttachment(),
+			"bigv_account":                   resourceBigvAccount(),
+			"bigv_ip_pool":                   resourceBigvIpPool(),
+			"bigv_user":                      resourceBigvUser(),
+			"bigv_api_key":                   resourceBigvApiKey(),
+			"bigv_load_balancer":             resourceBigvLoadBalancer(),
+			"bigv_certificate":               resourceBigvCertificate(),
+			"bigv_nat_rule":                  resourceBigvNatRule(),
+			"bigv_vlan":                      resourceBigvVlan(),
+			"bigv_migration":                 resourceBigvMigration(),
+			"bigv_reimage":                   resourceBigvReimage(),
+			"bigv_backup_policy":             resourceBigvBackupPolicy(),
+			"bigv_dns_zone":                  resourceBigvDnsZone(),
+			"bigv_monitoring_alert":          resourceBigvMonitoringAlert(),
+			"bigv_object_storage":            resourceBigvObjectStorage(),
+			"bigv_vpn":                       resourceBigvVpn(),
+			"bigv_affinity_group":            resourceBigvAffinityGroup(),
+			"bigv_affinity_group_membership": resourceBigvAffinityGroupMembership(),
+			"bigv_port_forward":              resourceBigvPortForward(),
+			"bigv_security_group":            resourceBigvSecurityGroup(),
+			"bigv_bandwidth_policy":          resourceBigvBandwidthPolicy(),
+			"bigv_schedule":                  resourceBigvSchedule(),
+			"bigv_ha_pair":                   resourceBigvHaPair(),
+			"bigv_dhcp_reservation":          resourceBigvDhcpReservation(),
+			"bigv_log_drain":                 resourceBigvLogDrain(),
+			"bigv_acl":                       resourceBigvAcl(),
+		},
+		DataSourcesMap: map[string]*schema.Resource{
+			"bigv_vm":    dataSourceBigvVM(),
+			"bigv_group": dataSourceBigvGroup(),
+			"bigv_zones": dataSourceBigvZones(),
+			"bigv_zone":  dataSourceBigvZone(),
+
+			"bigv_distributions":    dataSourceBigvDistributions(),
+			"bigv_distribution":     dataSourceBigvDistribution(),
+			"bigv_snapshot":         dataSourceBigvSnapshot(),
+			"bigv_firewall_rules":   dataSourceBigvFirewallRules(),
+			"bigv_account":          dataSourceBigvAccount(),
+			"bigv_ip_pool":          dataSourceBigvIpPool(),
+			"bigv_user":             dataSourceBigvUser(),
+			"bigv_certificate":      dataSourceBigvCertificate(),
+			"bigv_backup_policy":    dataSourceBigvBackupPolicy(),
+			"bigv_dns_zone":         dataSourceBigvDnsZone(),
+			"bigv_monitoring_alert": dataSourceBigvMonitoringAlert(),
 		},
-		ConfigureFunc: providerConfigure,
 	}
+
+	// The stop context is cancelled when terraform is interrupted, so long waits can give up
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, p.StopContext())
+	}
+
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (bigvClient interface{}, err error) {
+func providerConfigure(d *schema.ResourceData, stop context.Context) (bigvClient interface{}, err error) {
 
-	bigvClient = &client{
+	c := &client{
 		account:  d.Get("account").(string),
 		user:     d.Get("user").(string),
 		password: d.Get("password").(string),
+
+		totp:       d.Get("totp").(string),
+		totpSecret: d.Get("totp_secret").(string),
+
+		apiUrl:     strings.TrimRight(d.Get("api_url").(string), "/"),
+		authUrl:    d.Get("auth_url").(string),
+		apiVersion: d.Get("api_version").(string),
+
+		httpTimeout: d.Get("http_timeout").(int),
+
+		createPipelineMode: d.Get("create_pipeline_mode").(string),
+		creates:            make(chan struct{}, d.Get("max_concurrent_creates").(int)),
+
+		provisionPollInterval: time.Duration(d.Get("provision_poll_interval").(int)) * time.Second,
+		resizePollInterval:    time.Duration(d.Get("resize_poll_interval").(int)) * time.Second,
+
+		maintenanceRetryAttempts: d.Get("maintenance_retry_attempts").(int),
+		maintenanceRetryMaxWait:  time.Duration(d.Get("maintenance_retry_max_wait").(int)) * time.Second,
+
+		maxRetries:             d.Get("max_retries").(int),
+		retryBackoffMultiplier: d.Get("retry_backoff_multiplier").(float64),
+
+		max503Retries: d.Get("max_503_retries").(int),
+
+		validateOs: d.Get("validate_os").(bool),
+		debugHttp:  d.Get("debug_http").(bool),
+
+		defaultGroup: d.Get("default_group").(string),
+		defaultZone:  d.Get("default_zone").(string),
+
+		defaultOs:           strings.ToLower(d.Get("default_os").(string)),
+		defaultDiscSize:     d.Get("default_disc_size").(int),
+		defaultStorageGrade: d.Get("default_storage_grade").(string),
+
+		stop: stop,
+	}
+
+	// API keys don't expire, so they're used as a permanent session
+	if apiKey := d.Get("api_key").(string); apiKey != "" {
+		c.session = apiKey
+		c.apiKeyAuth = true
+	} else if c.user == "" || c.password == "" {
+		return nil, errors.New("Either api_key, or user and password, must be set")
+	}
+
+	// 0 or less means unlimited
+	if ops := d.Get("max_concurrent_operations").(int); ops > 0 {
+		c.operations = make(chan struct{}, ops)
+	}
+
+	bigvClient = c
+
+	return
+}
+
+var apiVersionRegexp = regexp.MustCompile(`^v[0-9]+$`)
+
+func validateApiVersion(v interface{}, k string) (ws []string, es []error) {
+	if !apiVersionRegexp.MatchString(v.(string)) {
+		es = append(es, fmt.Errorf("%s must be a version such as v1, got: %s", k, v))
 	}
+	return
+}
 
+// validateBackoffMultiplier
+// Less than 1 would wait less each time, rather than backing off
+func validateBackoffMultiplier(v interface{}, k string) (ws []string, es []error) {
+	if v.(float64) < 1 {
+		es = append(es, fmt.Errorf("%s must be at least 1, got: %v", k, v))
+	}
 	return
 }
@@ -0,0 +1,132 @@
+package bigv
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestProviderDefaultOsIsSupported(t *testing.T) {
+	s := Provider().(*schema.Provider).Schema["default_os"]
+
+	ws, es := s.ValidateFunc(s.Default, "default_os")
+	if len(ws) > 0 || len(es) > 0 {
+		t.Fatalf("expected default_os %s to be a supported os, got %v %v", s.Default, ws, es)
+	}
+
+	if ws, _ := s.ValidateFunc("vivid", "default_os"); len(ws) != 1 {
+		t.Fatalf("expected an end of life default_os to warn, got %v", ws)
+	}
+}
+
+func TestApiUri(t *testing.T) {
+	cases := []struct {
+		apiUrl     string
+		apiVersion string
+		expected   string
+	}{
+		{"https://uk0.bigv.io", "", "https://uk0.bigv.io"},
+		{"https://uk0.bigv.io", "v1", "https://uk0.bigv.io"},
+		{"https://uk0.bigv.io", "v2", "https://uk0.bigv.io/v2"},
+		{"https://uk0.bigv.io/", "v1", "https://uk0.bigv.io"},
+		{"https://uk0.bigv.io//", "v2", "https://uk0.bigv.io/v2"},
+		{"http://localhost:8080/bigv", "v1", "http://localhost:8080/bigv"},
+		{"http://localhost:8080/bigv/", "v3", "http://localhost:8080/bigv/v3"},
+	}
+
+	for _, tc := range cases {
+		c := &client{apiUrl: tc.apiUrl, apiVersion: tc.apiVersion}
+		if uri := c.apiUri(); uri != tc.expected {
+			t.Errorf("%s at %s: expected %s, got %s", tc.apiVersion, tc.apiUrl, tc.expected, uri)
+		}
+	}
+}
+
+func TestValidateApiVersion(t *testing.T) {
+	for _, version := range []string{"v1", "v2", "v10"} {
+		if _, es := validateApiVersion(version, "api_version"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", version, es)
+		}
+	}
+
+	for _, version := range []string{"", "2", "V2", "/v2", "v2/", "v2.1", "latest"} {
+		if _, es := validateApiVersion(version, "api_version"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", version)
+		}
+	}
+}
+
+func TestProviderApiUrl(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"account":     "myaccount",
+		"api_key":     "key",
+		"api_url":     "https://staging.example.com/bigv/",
+		"api_version": "v2",
+	})
+
+	meta, err := providerConfigure(d, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if uri := meta.(*client).apiUri(); uri != "https://staging.example.com/bigv/v2" {
+		t.Fatalf("expected the custom path with the version after, got %s", uri)
+	}
+}
+
+func TestVMRequestsUseApiVersion(t *testing.T) {
+	var paths []string
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/v2")
+		fake.ServeHTTP(w, r)
+	})
+	defer server.Close()
+
+	c.apiUrl = server.URL + "/"
+	c.apiVersion = "v2"
+
+	state := testVMRefresh(t, c, nil)
+
+	if len(paths) == 0 {
+		t.Fatal("expected the VM to be read from bigv")
+	}
+	for _, path := range paths {
+		if !strings.HasPrefix(path, "/v2/") || strings.Contains(path, "//") {
+			t.Errorf("expected every request under /v2/, got %s", path)
+		}
+	}
+
+	// resource_path is relative to wherever the API is, so it doesn't change
+	if path := state.Attributes["resource_path"]; path != "/accounts/myaccount/groups/12/virtual_machines/1" {
+		t.Errorf("expected the VM's path under its group id, got %s", path)
+	}
+}
+
+// These have no tests of their own, the others in the series check the same with theirs
+func TestResourcesLostFromBigv(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource *schema.Resource
+		raw      map[string]interface{}
+		id       string
+	}{
+		{"bigv_backup_policy", resourceBigvBackupPolicy(), map[string]interface{}{"vm_id": "1"}, "4"},
+		{"bigv_dns_zone", resourceBigvDnsZone(), map[string]interface{}{"domain": "example.com"}, "example.com"},
+		{"bigv_monitoring_alert", resourceBigvMonitoringAlert(), map[string]interface{}{"vm_id": "1"}, "5"},
+		{"bigv_affinity_group", resourceBigvAffinityGroup(), map[string]interface{}{"name": "web"}, "6"},
+		{"bigv_affinity_group_membership", resourceBigvAffinityGroupMembership(), map[string]interface{}{"affinity_group_id": "6", "vm_id": "1"}, "6/1"},
+		{"bigv_schedule", resourceBigvSchedule(), map[string]interface{}{"vm_id": "1"}, "7"},
+		{"bigv_ha_pair", resourceBigvHaPair(), map[string]interface{}{"primary_vm_id": "1", "secondary_vm_id": "2"}, "8"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testBigvNotFound(t, tc.resource, tc.raw, tc.id)
+		})
+	}
+}
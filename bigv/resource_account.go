@@ -0,0 +1,164 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvAccount struct {
+	Id           int    `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	BillingEmail string `json:"billing_email,omitempty"`
+	// Not omitempty, so that updates can unsuspend
+	Suspended bool `json:"suspended"`
+
+	// Quotas, 0 for unlimited
+	MaxVms    int `json:"max_vms"`
+	MaxCores  int `json:"max_cores"`
+	MaxMemory int `json:"max_memory"`
+}
+
+// accountSchema
+// Everything about an account but its name, shared with the bigv_account data source
+func accountSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"billing_email": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Where bigv sends the account's invoices",
+		},
+		"suspended": &schema.Schema{
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Suspending an account stops all its VMs",
+		},
+		"max_vms": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "The most VMs the account can have, 0 for no limit",
+		},
+		"max_cores": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "The most cores across all the account's VMs, 0 for no limit",
+		},
+		"max_memory": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			ValidateFunc: validation.IntAtLeast(0),
+			Description:  "The most memory in MiB across all the account's VMs, 0 for no limit",
+		},
+	}
+}
+
+// resourceBigvAccount
+// Child accounts, which need the provider's credentials to be allowed to make accounts
+func resourceBigvAccount() *schema.Resource {
+	s := accountSchema()
+	s["name"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Required: true,
+		ForceNew: true,
+	}
+
+	return &schema.Resource{
+		Create: resourceBigvAccountCreate,
+		Read:   resourceBigvAccountRead,
+		Update: resourceBigvAccountUpdate,
+		Delete: resourceBigvAccountDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: s,
+	}
+}
+
+func bigvAccountUrl(bigvClient *client, id string) string {
+	return fmt.Sprintf("%s/accounts/%s", bigvClient.apiUri(), id)
+}
+
+func accountFromResource(d *schema.ResourceData) bigvAccount {
+	return bigvAccount{
+		Name:         d.Get("name").(string),
+		BillingEmail: d.Get("billing_email").(string),
+		Suspended:    d.Get("suspended").(bool),
+		MaxVms:       d.Get("max_vms").(int),
+		MaxCores:     d.Get("max_cores").(int),
+		MaxMemory:    d.Get("max_memory").(int),
+	}
+}
+
+func accountToResource(d *schema.ResourceData, account *bigvAccount) {
+	d.Set("name", account.Name)
+	d.Set("billing_email", account.BillingEmail)
+	d.Set("suspended", account.Suspended)
+	d.Set("max_vms", account.MaxVms)
+	d.Set("max_cores", account.MaxCores)
+	d.Set("max_memory", account.MaxMemory)
+}
+
+func resourceBigvAccountCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := fmt.Sprintf("%s/accounts", bigvClient.apiUri())
+
+	account := &bigvAccount{}
+	if _, err := bigvClient.doJson("POST", url, accountFromResource(d), account); err != nil {
+		return err
+	}
+
+	if account.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for account %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(account.Id))
+
+	log.Printf("[DEBUG] Created BigV account, Id: %s", d.Id())
+
+	return resourceBigvAccountRead(d, meta)
+}
+
+func resourceBigvAccountRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	account := &bigvAccount{}
+	status, err := bigvClient.doJson("GET", bigvAccountUrl(bigvClient, d.Id()), nil, account)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Account %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	accountToResource(d, account)
+
+	return nil
+}
+
+func resourceBigvAccountUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if _, err := bigvClient.doJson("PUT", bigvAccountUrl(bigvClient, d.Id()), accountFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvAccountRead(d, meta)
+}
+
+func resourceBigvAccountDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvAccountUrl(bigvClient, d.Id()), nil, nil)
+	return err
+}
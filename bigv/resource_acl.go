@@ -0,0 +1,187 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvAcl struct {
+	Id           int      `json:"id,omitempty"`
+	ResourceType string   `json:"resource_type,omitempty"`
+	ResourceId   string   `json:"resource_id,omitempty"`
+	Principal    string   `json:"principal,omitempty"`
+	Actions      []string `json:"actions,omitempty"`
+	Effect       string   `json:"effect,omitempty"`
+}
+
+func resourceBigvAcl() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvAclCreate,
+		Read:   resourceBigvAclRead,
+		Update: resourceBigvAclUpdate,
+		Delete: resourceBigvAclDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"resource_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"vm", "group", "account"}, false),
+			},
+			"resource_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"principal": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateAclPrincipal,
+				Description:  "A user name, or a network in CIDR notation that requests come from",
+			},
+			"actions": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The API operations the entry covers, e.g. read, update, delete or power",
+			},
+			"effect": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "allow",
+				ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+			},
+		},
+	}
+}
+
+var aclUserRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// validateAclPrincipal
+// Anything with a / has to be a network, otherwise it's a user
+func validateAclPrincipal(v interface{}, k string) (ws []string, es []error) {
+	principal := v.(string)
+
+	if strings.Contains(principal, "/") {
+		if _, _, err := net.ParseCIDR(principal); err != nil {
+			es = append(es, fmt.Errorf("%s must be a network in CIDR notation, e.g. 192.0.2.0/24, got: %s", k, principal))
+		}
+		return
+	}
+
+	if !aclUserRegexp.MatchString(principal) {
+		es = append(es, fmt.Errorf("%s must be a user name or a network in CIDR notation, got: %s", k, principal))
+	}
+	return
+}
+
+func bigvAclsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/acls",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func aclActions(d *schema.ResourceData) []string {
+	actions := []string{}
+	for _, action := range d.Get("actions").(*schema.Set).List() {
+		actions = append(actions, action.(string))
+	}
+	return actions
+}
+
+func resourceBigvAclCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	acl := &bigvAcl{}
+	_, err := bigvClient.doJson("POST", bigvAclsUrl(bigvClient), bigvAcl{
+		ResourceType: d.Get("resource_type").(string),
+		ResourceId:   d.Get("resource_id").(string),
+		Principal:    d.Get("principal").(string),
+		Actions:      aclActions(d),
+		Effect:       d.Get("effect").(string),
+	}, acl)
+	if err != nil {
+		return err
+	}
+
+	if acl.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the ACL entry for %s on %s %s", d.Get("principal"), d.Get("resource_type"), d.Get("resource_id"))
+	}
+
+	d.SetId(strconv.Itoa(acl.Id))
+
+	log.Printf("[DEBUG] Created BigV ACL entry, Id: %s", d.Id())
+
+	return resourceBigvAclRead(d, meta)
+}
+
+func resourceBigvAclRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	acl := &bigvAcl{}
+	status, err := bigvClient.doJson("GET", bigvAclsUrl(bigvClient)+"/"+d.Id(), nil, acl)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] ACL entry %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	actions := make([]interface{}, len(acl.Actions))
+	for i, action := range acl.Actions {
+		actions[i] = action
+	}
+
+	d.Set("resource_type", acl.ResourceType)
+	d.Set("resource_id", acl.ResourceId)
+	d.Set("principal", acl.Principal)
+	d.Set("actions", schema.NewSet(schema.HashString, actions))
+	d.Set("effect", acl.Effect)
+
+	return nil
+}
+
+// resourceBigvAclUpdate
+// Only what's allowed changes, who and what it applies to makes a new entry
+func resourceBigvAclUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvAclsUrl(bigvClient)+"/"+d.Id(), bigvAcl{
+		Actions: aclActions(d),
+		Effect:  d.Get("effect").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvAclRead(d, meta)
+}
+
+func resourceBigvAclDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvAclsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
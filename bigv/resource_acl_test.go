@@ -0,0 +1,78 @@
+package bigv
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestValidateAclPrincipal(t *testing.T) {
+	valid := []string{"alice", "deploy-bot", "ops.team", "192.0.2.0/24", "2001:db8::/32"}
+	for _, principal := range valid {
+		if _, es := validateAclPrincipal(principal, "principal"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", principal, es)
+		}
+	}
+
+	invalid := []string{"", "Alice", "-bot", "192.0.2.0/33", "alice/admin", "ops team"}
+	for _, principal := range invalid {
+		if _, es := validateAclPrincipal(principal, "principal"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", principal)
+		}
+	}
+}
+
+func TestAclRoundTrip(t *testing.T) {
+	acl := `{"id":11,"resource_type":"vm","resource_id":"1","principal":"192.0.2.0/24","actions":["read","power"],"effect":"allow"}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvAcl(), map[string]interface{}{
+		"resource_type": "vm",
+		"resource_id":   "1",
+		"principal":     "192.0.2.0/24",
+		"actions":       []interface{}{"read", "power"},
+	}, map[string]interface{}{
+		"effect": "deny",
+	}, []testBigvCall{
+		{"POST", "/accounts/myaccount/acls", 0, acl},
+		{"GET", "/accounts/myaccount/acls/11", 0, acl},
+		{"PUT", "/accounts/myaccount/acls/11", 0, acl},
+		{"GET", "/accounts/myaccount/acls/11", 0, acl},
+		{"DELETE", "/accounts/myaccount/acls/11", http.StatusNoContent, ""},
+	})
+
+	create := sent[0]
+	if create["resource_type"] != "vm" || create["resource_id"] != "1" || create["principal"] != "192.0.2.0/24" || create["effect"] != "allow" ||
+		testAclActions(create) != "power,read" {
+		t.Errorf("expected 192.0.2.0/24 to be allowed to power and read VM 1, got %v", create)
+	}
+
+	// Who and what it's for can't change, so they're left out of the update
+	update := sent[2]
+	if len(update) != 2 || update["effect"] != "deny" || testAclActions(update) != "power,read" {
+		t.Errorf("expected only the actions and effect to be updated, got %v", update)
+	}
+
+	if d.Id() != "11" || d.Get("actions").(*schema.Set).Len() != 2 {
+		t.Errorf("expected entry 11 with 2 actions, got %s with %v", d.Id(), d.Get("actions"))
+	}
+}
+
+// testAclActions
+// The actions sent, sorted, as they're a set
+func testAclActions(body map[string]interface{}) string {
+	var actions []string
+	list, _ := body["actions"].([]interface{})
+	for _, action := range list {
+		actions = append(actions, action.(string))
+	}
+
+	sort.Strings(actions)
+	return strings.Join(actions, ",")
+}
+
+func TestAclNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvAcl(), map[string]interface{}{"resource_type": "vm"}, "11")
+}
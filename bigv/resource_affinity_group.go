@@ -0,0 +1,254 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvAffinityGroup struct {
+	Id     int    `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Policy string `json:"policy,omitempty"`
+
+	// Read Attributes
+	VmIds []int `json:"virtual_machine_ids,omitempty"`
+}
+
+func resourceBigvAffinityGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvAffinityGroupCreate,
+		Read:   resourceBigvAffinityGroupRead,
+		Update: resourceBigvAffinityGroupUpdate,
+		Delete: resourceBigvAffinityGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"policy": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "spread",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"spread", "pack"}, false),
+				Description:  "spread puts members on different hosts, pack on the same ones",
+			},
+			"vm_ids": &schema.Schema{
+				Type:     schema.TypeSet,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Set:      schema.HashString,
+			},
+		},
+	}
+}
+
+func bigvAffinityGroupsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/affinity_groups",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvAffinityGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group := &bigvAffinityGroup{}
+	_, err := bigvClient.doJson("POST", bigvAffinityGroupsUrl(bigvClient), bigvAffinityGroup{
+		Name:   d.Get("name").(string),
+		Policy: d.Get("policy").(string),
+	}, group)
+	if err != nil {
+		return err
+	}
+
+	if group.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for affinity group %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	log.Printf("[DEBUG] Created BigV affinity group, Id: %s", d.Id())
+
+	return resourceBigvAffinityGroupRead(d, meta)
+}
+
+func resourceBigvAffinityGroupRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group, err := readBigvAffinityGroup(bigvClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if group == nil {
+		log.Printf("[WARN] Affinity group %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	vmIds := make([]interface{}, len(group.VmIds))
+	for i, id := range group.VmIds {
+		vmIds[i] = strconv.Itoa(id)
+	}
+
+	d.Set("name", group.Name)
+	d.Set("policy", group.Policy)
+	d.Set("vm_ids", schema.NewSet(schema.HashString, vmIds))
+
+	return nil
+}
+
+// readBigvAffinityGroup
+// nil without an error when bigv doesn't have it
+func readBigvAffinityGroup(bigvClient *client, id string) (*bigvAffinityGroup, error) {
+	group := &bigvAffinityGroup{}
+	status, err := bigvClient.doJson("GET", bigvAffinityGroupsUrl(bigvClient)+"/"+id, nil, group)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// resourceBigvAffinityGroupUpdate
+// Only renames, a new policy would mean moving every member
+func resourceBigvAffinityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvAffinityGroupsUrl(bigvClient)+"/"+d.Id(), bigvAffinityGroup{
+		Name: d.Get("name").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvAffinityGroupRead(d, meta)
+}
+
+func resourceBigvAffinityGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvAffinityGroupsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
+
+func resourceBigvAffinityGroupMembership() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvAffinityGroupMembershipCreate,
+		Read:   resourceBigvAffinityGroupMembershipRead,
+		Delete: resourceBigvAffinityGroupMembershipDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceBigvAffinityGroupMembershipImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"affinity_group_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func bigvAffinityGroupMembersUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/%s/members",
+		bigvAffinityGroupsUrl(bigvClient),
+		d.Get("affinity_group_id"),
+	)
+}
+
+// resourceBigvAffinityGroupMembershipCreate
+// bigv moves the VM to a suitable host if it isn't on one already
+func resourceBigvAffinityGroupMembershipCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vmId, err := strconv.Atoi(d.Get("vm_id").(string))
+	if err != nil {
+		return fmt.Errorf("vm_id must be a numeric VM id, got: %s", d.Get("vm_id"))
+	}
+
+	_, err = bigvClient.doJson("POST", bigvAffinityGroupMembersUrl(d, bigvClient), map[string]int{
+		"virtual_machine_id": vmId,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s", d.Get("affinity_group_id"), d.Get("vm_id")))
+
+	return resourceBigvAffinityGroupMembershipRead(d, meta)
+}
+
+func resourceBigvAffinityGroupMembershipRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group, err := readBigvAffinityGroup(bigvClient, d.Get("affinity_group_id").(string))
+	if err != nil {
+		return err
+	}
+
+	if group != nil {
+		for _, id := range group.VmIds {
+			if strconv.Itoa(id) == d.Get("vm_id").(string) {
+				return nil
+			}
+		}
+	}
+
+	log.Printf("[WARN] VM %s not in affinity group %s, removing membership from state", d.Get("vm_id"), d.Get("affinity_group_id"))
+	d.SetId("")
+	return nil
+}
+
+func resourceBigvAffinityGroupMembershipDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvAffinityGroupMembersUrl(d, bigvClient) + "/" + d.Get("vm_id").(string)
+
+	status, err := bigvClient.doJson("DELETE", url, nil, nil)
+	if status == http.StatusNotFound {
+		// The VM or group went first
+		d.SetId("")
+		return nil
+	}
+
+	return err
+}
+
+// resourceBigvAffinityGroupMembershipImport
+// Memberships are known as affinity_group_id/vm_id
+func resourceBigvAffinityGroupMembershipImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("Affinity group memberships are imported as affinity_group_id/vm_id, got: %s", d.Id())
+	}
+
+	d.Set("affinity_group_id", parts[0])
+	d.Set("vm_id", parts[1])
+
+	return []*schema.ResourceData{d}, nil
+}
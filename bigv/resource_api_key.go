@@ -0,0 +1,162 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvApiKey struct {
+	Id     int      `json:"id,omitempty"`
+	Label  string   `json:"label,omitempty"`
+	Scopes []string `json:"scopes,omitempty"`
+	Expiry string   `json:"expires_at,omitempty"`
+
+	// Only sent back when the key is made or rotated
+	Key string `json:"api_key,omitempty"`
+}
+
+func resourceBigvApiKey() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvApiKeyCreate,
+		Read:   resourceBigvApiKeyRead,
+		Update: resourceBigvApiKeyUpdate,
+		Delete: resourceBigvApiKeyDelete,
+
+		CustomizeDiff: resourceBigvApiKeyCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"scopes": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The operations the key allows. Everything the user can do if not set",
+			},
+			"expiry": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRFC3339,
+				Description:  "When the key stops working, e.g. 2017-01-31T00:00:00Z. Never by default",
+			},
+			"rotate_keys_on_apply": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Replace the key with a new one on every apply",
+			},
+			"key": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+// resourceBigvApiKeyCustomizeDiff
+// Rotating shows up as a new key to come, so every apply has something to do
+func resourceBigvApiKeyCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.Get("rotate_keys_on_apply").(bool) {
+		return nil
+	}
+
+	return d.SetNewComputed("key")
+}
+
+func bigvApiKeysUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/api_keys",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvApiKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var scopes []string
+	for _, scope := range d.Get("scopes").([]interface{}) {
+		scopes = append(scopes, scope.(string))
+	}
+
+	key := &bigvApiKey{}
+	_, err := bigvClient.doJson("POST", bigvApiKeysUrl(bigvClient), bigvApiKey{
+		Label:  d.Get("label").(string),
+		Scopes: scopes,
+		Expiry: d.Get("expiry").(string),
+	}, key)
+	if err != nil {
+		return err
+	}
+
+	if key.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for api key %s", d.Get("label"))
+	}
+
+	d.SetId(strconv.Itoa(key.Id))
+
+	// There's no getting it back later
+	d.Set("key", key.Key)
+
+	log.Printf("[DEBUG] Created BigV API key, Id: %s", d.Id())
+
+	return resourceBigvApiKeyRead(d, meta)
+}
+
+// resourceBigvApiKeyRead
+// Never sets key, bigv only gives it out once
+func resourceBigvApiKeyRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	key := &bigvApiKey{}
+	status, err := bigvClient.doJson("GET", bigvApiKeysUrl(bigvClient)+"/"+d.Id(), nil, key)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] API key %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("label", key.Label)
+	d.Set("scopes", key.Scopes)
+	d.Set("expiry", key.Expiry)
+
+	return nil
+}
+
+// resourceBigvApiKeyUpdate
+// The only thing to do in place is rotating
+func resourceBigvApiKeyUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if d.Get("rotate_keys_on_apply").(bool) {
+		key := &bigvApiKey{}
+		if _, err := bigvClient.doJson("POST", bigvApiKeysUrl(bigvClient)+"/"+d.Id()+"/rotate", nil, key); err != nil {
+			return err
+		}
+
+		d.Set("key", key.Key)
+
+		log.Printf("[DEBUG] Rotated BigV API key, Id: %s", d.Id())
+	}
+
+	return resourceBigvApiKeyRead(d, meta)
+}
+
+func resourceBigvApiKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvApiKeysUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
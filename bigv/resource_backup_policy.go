@@ -0,0 +1,175 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvBackupPolicy struct {
+	Id             int    `json:"id,omitempty"`
+	Frequency      string `json:"frequency,omitempty"`
+	RetentionCount int    `json:"retention_count,omitempty"`
+	StartTime      string `json:"start_time,omitempty"`
+	// Not omitempty, so that updates can disable it
+	Enabled bool `json:"enabled"`
+
+	// Read Attributes
+	NextBackupTime string `json:"next_backup_at,omitempty"`
+}
+
+var backupFrequencies = []string{"hourly", "daily", "weekly"}
+
+var startTimeRegexp = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]$`)
+
+func resourceBigvBackupPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvBackupPolicyCreate,
+		Read:   resourceBigvBackupPolicyRead,
+		Update: resourceBigvBackupPolicyUpdate,
+		Delete: resourceBigvBackupPolicyDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The VM's group. Defaults to the provider's default_group",
+			},
+			"frequency": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(backupFrequencies, false),
+			},
+			"retention_count": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      7,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "How many backups to keep, the oldest are deleted as new ones are taken",
+			},
+			"start_time": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "00:00",
+				ValidateFunc: validation.StringMatch(startTimeRegexp, "must be a UTC time such as 02:30"),
+				Description:  "The UTC time of day backups are taken from",
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"next_backup_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvBackupPoliciesUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s/backup_schedules",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Get("vm_id"),
+	)
+}
+
+func backupPolicyFromResource(d *schema.ResourceData) bigvBackupPolicy {
+	return bigvBackupPolicy{
+		Frequency:      d.Get("frequency").(string),
+		RetentionCount: d.Get("retention_count").(int),
+		StartTime:      d.Get("start_time").(string),
+		Enabled:        d.Get("enabled").(bool),
+	}
+}
+
+func backupPolicyToResource(d *schema.ResourceData, policy *bigvBackupPolicy) {
+	d.Set("frequency", policy.Frequency)
+	d.Set("retention_count", policy.RetentionCount)
+	d.Set("start_time", policy.StartTime)
+	d.Set("enabled", policy.Enabled)
+	d.Set("next_backup_time", policy.NextBackupTime)
+}
+
+func resourceBigvBackupPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset group is filled in here
+	if d.Get("group").(string) == "" {
+		d.Set("group", bigvClient.defaultGroup)
+	}
+
+	policy := &bigvBackupPolicy{}
+	if _, err := bigvClient.doJson("POST", bigvBackupPoliciesUrl(d, bigvClient), backupPolicyFromResource(d), policy); err != nil {
+		return err
+	}
+
+	if policy.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the backup policy on VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(policy.Id))
+
+	log.Printf("[DEBUG] Created BigV backup policy, Id: %s", d.Id())
+
+	return resourceBigvBackupPolicyRead(d, meta)
+}
+
+func resourceBigvBackupPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	policy := &bigvBackupPolicy{}
+	status, err := bigvClient.doJson("GET", bigvBackupPoliciesUrl(d, bigvClient)+"/"+d.Id(), nil, policy)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Backup policy %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	backupPolicyToResource(d, policy)
+
+	return nil
+}
+
+// resourceBigvBackupPolicyUpdate
+// Everything but the VM changes in place, the VM itself is left alone
+func resourceBigvBackupPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if _, err := bigvClient.doJson("PUT", bigvBackupPoliciesUrl(d, bigvClient)+"/"+d.Id(), backupPolicyFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvBackupPolicyRead(d, meta)
+}
+
+// resourceBigvBackupPolicyDelete
+// Backups already taken are kept
+func resourceBigvBackupPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvBackupPoliciesUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
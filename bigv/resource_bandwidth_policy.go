@@ -0,0 +1,165 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvBandwidthPolicy struct {
+	IngressLimit  int `json:"ingress_limit_mbps,omitempty"`
+	EgressLimit   int `json:"egress_limit_mbps,omitempty"`
+	BurstDuration int `json:"burst_duration_seconds"`
+}
+
+type bigvBandwidthMetrics struct {
+	CurrentUtilization float64 `json:"current_utilization_mbps"`
+}
+
+// resourceBigvBandwidthPolicy
+// A VM has at most one policy, so it's identified by the VM's id
+func resourceBigvBandwidthPolicy() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvBandwidthPolicyCreate,
+		Read:   resourceBigvBandwidthPolicyRead,
+		Update: resourceBigvBandwidthPolicyUpdate,
+		Delete: resourceBigvBandwidthPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceBigvBandwidthPolicyImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"ingress_limit_mbps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"egress_limit_mbps": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"burst_duration_seconds": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 3600),
+				Description:  "How long the VM can go over its limits before being shaped",
+			},
+			"current_utilization_mbps": &schema.Schema{
+				Type:        schema.TypeFloat,
+				Computed:    true,
+				Description: "The VM's throughput when last read",
+			},
+		},
+	}
+}
+
+func bigvBandwidthPolicyUrl(bigvClient *client, vmId string) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/qos",
+		bigvClient.apiUri(),
+		vmId,
+	)
+}
+
+func bandwidthPolicyFromResource(d *schema.ResourceData) bigvBandwidthPolicy {
+	return bigvBandwidthPolicy{
+		IngressLimit:  d.Get("ingress_limit_mbps").(int),
+		EgressLimit:   d.Get("egress_limit_mbps").(int),
+		BurstDuration: d.Get("burst_duration_seconds").(int),
+	}
+}
+
+func resourceBigvBandwidthPolicyCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vmId := d.Get("vm_id").(string)
+	_, err := bigvClient.doJson("PUT", bigvBandwidthPolicyUrl(bigvClient, vmId), bandwidthPolicyFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(vmId)
+
+	log.Printf("[DEBUG] Created BigV bandwidth policy for VM %s", d.Id())
+
+	return resourceBigvBandwidthPolicyRead(d, meta)
+}
+
+func resourceBigvBandwidthPolicyRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	policy := &bigvBandwidthPolicy{}
+	status, err := bigvClient.doJson("GET", bigvBandwidthPolicyUrl(bigvClient, d.Id()), nil, policy)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Bandwidth policy for VM %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("vm_id", d.Id())
+	d.Set("ingress_limit_mbps", policy.IngressLimit)
+	d.Set("egress_limit_mbps", policy.EgressLimit)
+	d.Set("burst_duration_seconds", policy.BurstDuration)
+
+	return readBigvBandwidthUtilization(d, bigvClient)
+}
+
+// readBigvBandwidthUtilization
+// Throughput comes from the metrics API rather than the policy
+func readBigvBandwidthUtilization(d *schema.ResourceData, bigvClient *client) error {
+	url := fmt.Sprintf("%s/virtual_machines/%s/metrics/bandwidth",
+		bigvClient.apiUri(),
+		d.Id(),
+	)
+
+	metrics := &bigvBandwidthMetrics{}
+	if _, err := bigvClient.doJson("GET", url, nil, metrics); err != nil {
+		return err
+	}
+
+	d.Set("current_utilization_mbps", metrics.CurrentUtilization)
+
+	return nil
+}
+
+func resourceBigvBandwidthPolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvBandwidthPolicyUrl(bigvClient, d.Id()), bandwidthPolicyFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvBandwidthPolicyRead(d, meta)
+}
+
+// resourceBigvBandwidthPolicyDelete
+// Removes the limits, the VM itself is left alone
+func resourceBigvBandwidthPolicyDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvBandwidthPolicyUrl(bigvClient, d.Id()), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
+
+func resourceBigvBandwidthPolicyImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	d.Set("vm_id", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,44 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestBandwidthPolicyRoundTrip(t *testing.T) {
+	// bigv says there's no burst, which has to be sent back with the update rather than left out
+	policy := `{"ingress_limit_mbps":100,"egress_limit_mbps":50,"burst_duration_seconds":0}`
+	metrics := `{"current_utilization_mbps":12.5}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvBandwidthPolicy(), map[string]interface{}{
+		"vm_id":                  "1",
+		"ingress_limit_mbps":     100,
+		"egress_limit_mbps":      50,
+		"burst_duration_seconds": 30,
+	}, nil, []testBigvCall{
+		{"PUT", "/virtual_machines/1/qos", 0, policy},
+		{"GET", "/virtual_machines/1/qos", 0, policy},
+		{"GET", "/virtual_machines/1/metrics/bandwidth", 0, metrics},
+		{"PUT", "/virtual_machines/1/qos", 0, policy},
+		{"GET", "/virtual_machines/1/qos", 0, policy},
+		{"GET", "/virtual_machines/1/metrics/bandwidth", 0, metrics},
+		{"DELETE", "/virtual_machines/1/qos", http.StatusNoContent, ""},
+	})
+
+	testBigvSent(t, "create", sent[0], map[string]interface{}{
+		"ingress_limit_mbps":     float64(100),
+		"egress_limit_mbps":      float64(50),
+		"burst_duration_seconds": float64(30),
+	})
+	if v, ok := sent[3]["burst_duration_seconds"]; !ok || v != float64(0) {
+		t.Errorf("expected the burst to be turned off, got %v", sent[3])
+	}
+
+	if d.Id() != "1" || d.Get("current_utilization_mbps") != 12.5 {
+		t.Errorf("expected VM 1's policy at 12.5mbps, got %s at %v", d.Id(), d.Get("current_utilization_mbps"))
+	}
+}
+
+func TestBandwidthPolicyNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvBandwidthPolicy(), map[string]interface{}{"vm_id": "1"}, "1")
+}
@@ -0,0 +1,206 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvCertificate struct {
+	Id        int      `json:"id,omitempty"`
+	Domains   []string `json:"domains,omitempty"`
+	AutoRenew bool     `json:"auto_renew"`
+
+	// Read Attributes
+	CertificatePem string `json:"certificate,omitempty"`
+	PrivateKeyPem  string `json:"private_key,omitempty"`
+	Expiry         string `json:"expires_at,omitempty"`
+	Fingerprint    string `json:"fingerprint,omitempty"`
+}
+
+func resourceBigvCertificate() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvCertificateCreate,
+		Read:   resourceBigvCertificateRead,
+		Update: resourceBigvCertificateUpdate,
+		Delete: resourceBigvCertificateDelete,
+
+		CustomizeDiff: resourceBigvCertificateCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"domains": &schema.Schema{
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names the certificate is for, the first being its common name",
+			},
+			"auto_renew": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Renew the certificate on apply once it's within renew_before_expiry_days of expiring",
+			},
+			"renew_before_expiry_days": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      30,
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"certificate_pem": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"private_key_pem": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"expiry": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"fingerprint": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceBigvCertificateCustomizeDiff
+// Certificates due for renewal plan new contents, so the apply renews them
+func resourceBigvCertificateCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.Get("auto_renew").(bool) {
+		return nil
+	}
+
+	if !certificateDueForRenewal(d.Get("expiry").(string), d.Get("renew_before_expiry_days").(int), time.Now()) {
+		return nil
+	}
+
+	log.Printf("[INFO] Certificate %s expires %s, planning to renew it", d.Id(), d.Get("expiry"))
+
+	for _, key := range []string{"certificate_pem", "private_key_pem", "expiry", "fingerprint"} {
+		if err := d.SetNewComputed(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// certificateDueForRenewal
+// Unparseable expiries are left alone rather than renewed every apply
+func certificateDueForRenewal(expiry string, days int, now time.Time) bool {
+	expires, err := time.Parse(time.RFC3339, expiry)
+	if err != nil {
+		return false
+	}
+
+	return now.AddDate(0, 0, days).After(expires)
+}
+
+func bigvCertificatesUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/certificates",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvCertificateCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	var domains []string
+	for _, domain := range d.Get("domains").([]interface{}) {
+		domains = append(domains, domain.(string))
+	}
+
+	cert := &bigvCertificate{}
+	_, err := bigvClient.doJson("POST", bigvCertificatesUrl(bigvClient), bigvCertificate{
+		Domains:   domains,
+		AutoRenew: d.Get("auto_renew").(bool),
+	}, cert)
+	if err != nil {
+		return err
+	}
+
+	if cert.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the certificate for %s", domains[0])
+	}
+
+	d.SetId(strconv.Itoa(cert.Id))
+
+	log.Printf("[DEBUG] Created BigV certificate, Id: %s", d.Id())
+
+	return resourceBigvCertificateRead(d, meta)
+}
+
+func resourceBigvCertificateRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	cert := &bigvCertificate{}
+	status, err := bigvClient.doJson("GET", bigvCertificatesUrl(bigvClient)+"/"+d.Id(), nil, cert)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Certificate %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	certificateToResource(d, cert)
+
+	return nil
+}
+
+func certificateToResource(d *schema.ResourceData, cert *bigvCertificate) {
+	d.Set("domains", cert.Domains)
+	d.Set("auto_renew", cert.AutoRenew)
+	d.Set("certificate_pem", cert.CertificatePem)
+	d.Set("private_key_pem", cert.PrivateKeyPem)
+	d.Set("expiry", cert.Expiry)
+	d.Set("fingerprint", cert.Fingerprint)
+}
+
+// resourceBigvCertificateUpdate
+// Saves auto_renew, and renews the certificate if it's due
+func resourceBigvCertificateUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvCertificatesUrl(bigvClient) + "/" + d.Id()
+
+	if d.HasChange("auto_renew") {
+		if _, err := bigvClient.doJson("PUT", url, bigvCertificate{
+			AutoRenew: d.Get("auto_renew").(bool),
+		}, nil); err != nil {
+			return err
+		}
+	}
+
+	// The plan left the new expiry unknown, so check the current one
+	expiry, _ := d.GetChange("expiry")
+	if d.Get("auto_renew").(bool) && certificateDueForRenewal(expiry.(string), d.Get("renew_before_expiry_days").(int), time.Now()) {
+		if _, err := bigvClient.doJson("POST", url+"/renew", nil, nil); err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Renewed BigV certificate, Id: %s", d.Id())
+	}
+
+	return resourceBigvCertificateRead(d, meta)
+}
+
+func resourceBigvCertificateDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvCertificatesUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,223 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvDhcpReservation struct {
+	Id            int    `json:"id,omitempty"`
+	MacAddress    string `json:"mac_address,omitempty"`
+	IpAddress     string `json:"ip_address,omitempty"`
+	Hostname      string `json:"hostname"`
+	LeaseDuration int    `json:"lease_duration,omitempty"`
+}
+
+func resourceBigvDhcpReservation() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvDhcpReservationCreate,
+		Read:   resourceBigvDhcpReservationRead,
+		Update: resourceBigvDhcpReservationUpdate,
+		Delete: resourceBigvDhcpReservationDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		CustomizeDiff: resourceBigvDhcpReservationCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"mac_address": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vm_id"},
+				ValidateFunc:  validateMacAddress,
+				StateFunc:     lowercase,
+			},
+			"vm_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"mac_address"},
+				Description:   "A VM whose first network interface's mac address to use, instead of mac_address",
+			},
+			"ip_address": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIPv4,
+			},
+			"hostname": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringMatch(hostnameRegexp, "must be a valid hostname"),
+			},
+			"lease_duration": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      86400,
+				ValidateFunc: validation.IntAtLeast(60),
+				Description:  "Seconds each lease lasts",
+			},
+		},
+	}
+}
+
+func validateMacAddress(v interface{}, k string) (ws []string, es []error) {
+	if _, err := net.ParseMAC(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be a mac address, e.g. fe:ff:00:00:00:01, got: %s", k, v))
+	}
+	return
+}
+
+// resourceBigvDhcpReservationCustomizeDiff
+// Reservations following a VM need updating whenever its mac address changes
+func resourceBigvDhcpReservationCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if !d.NewValueKnown("vm_id") {
+		return d.SetNewComputed("mac_address")
+	}
+
+	vmId := d.Get("vm_id").(string)
+	if vmId == "" {
+		if _, ok := d.GetOk("mac_address"); !ok {
+			return fmt.Errorf("One of mac_address or vm_id must be set")
+		}
+		return nil
+	}
+
+	mac, err := dhcpReservationVMMac(bigvClient, vmId)
+	if err != nil {
+		return err
+	}
+
+	if mac != d.Get("mac_address").(string) {
+		return d.SetNew("mac_address", mac)
+	}
+
+	return nil
+}
+
+// dhcpReservationVMMac
+// The mac address of the VM's first network interface
+func dhcpReservationVMMac(bigvClient *client, vmId string) (string, error) {
+	vm, status, err := readBigvVMOverview(bigvClient, vmId)
+	if err != nil {
+		return "", err
+	}
+	if vm == nil {
+		return "", fmt.Errorf("VM %s not found in bigv, status %d", vmId, status)
+	}
+
+	if len(vm.Nics) == 0 || vm.Nics[0].Mac == "" {
+		return "", fmt.Errorf("VM %s has no network interface with a mac address", vmId)
+	}
+
+	return strings.ToLower(vm.Nics[0].Mac), nil
+}
+
+func bigvDhcpReservationsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/dhcp_reservations",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func dhcpReservationFromResource(d *schema.ResourceData) bigvDhcpReservation {
+	return bigvDhcpReservation{
+		MacAddress:    d.Get("mac_address").(string),
+		IpAddress:     d.Get("ip_address").(string),
+		Hostname:      d.Get("hostname").(string),
+		LeaseDuration: d.Get("lease_duration").(int),
+	}
+}
+
+func resourceBigvDhcpReservationCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// vm_id wasn't known when planning, so look the mac address up now
+	if vmId := d.Get("vm_id").(string); vmId != "" && d.Get("mac_address").(string) == "" {
+		mac, err := dhcpReservationVMMac(bigvClient, vmId)
+		if err != nil {
+			return err
+		}
+		d.Set("mac_address", mac)
+	}
+
+	reservation := &bigvDhcpReservation{}
+	_, err := bigvClient.doJson("POST", bigvDhcpReservationsUrl(bigvClient), dhcpReservationFromResource(d), reservation)
+	if err != nil {
+		return err
+	}
+
+	if reservation.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the DHCP reservation of %s", d.Get("ip_address"))
+	}
+
+	d.SetId(strconv.Itoa(reservation.Id))
+
+	log.Printf("[DEBUG] Created BigV DHCP reservation, Id: %s", d.Id())
+
+	return resourceBigvDhcpReservationRead(d, meta)
+}
+
+func resourceBigvDhcpReservationRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	reservation := &bigvDhcpReservation{}
+	status, err := bigvClient.doJson("GET", bigvDhcpReservationsUrl(bigvClient)+"/"+d.Id(), nil, reservation)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] DHCP reservation %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("mac_address", strings.ToLower(reservation.MacAddress))
+	d.Set("ip_address", reservation.IpAddress)
+	d.Set("hostname", reservation.Hostname)
+	d.Set("lease_duration", reservation.LeaseDuration)
+
+	return nil
+}
+
+// resourceBigvDhcpReservationUpdate
+// VMs pick up the change when their lease is next renewed
+func resourceBigvDhcpReservationUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if vmId := d.Get("vm_id").(string); vmId != "" && d.Get("mac_address").(string) == "" {
+		mac, err := dhcpReservationVMMac(bigvClient, vmId)
+		if err != nil {
+			return err
+		}
+		d.Set("mac_address", mac)
+	}
+
+	_, err := bigvClient.doJson("PUT", bigvDhcpReservationsUrl(bigvClient)+"/"+d.Id(), dhcpReservationFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvDhcpReservationRead(d, meta)
+}
+
+func resourceBigvDhcpReservationDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvDhcpReservationsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
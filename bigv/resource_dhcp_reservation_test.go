@@ -0,0 +1,40 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDhcpReservationRoundTrip(t *testing.T) {
+	reservation := `{"id":6,"mac_address":"FE:FF:00:00:00:01","ip_address":"10.0.0.5","hostname":"","lease_duration":86400}`
+
+	// The mac address is only looked up while it isn't known
+	d, sent := testBigvRoundTrip(t, resourceBigvDhcpReservation(), map[string]interface{}{
+		"vm_id":      "1",
+		"ip_address": "10.0.0.5",
+	}, nil, []testBigvCall{
+		{"GET", "/virtual_machines/1?view=overview", 0, `{"id":1,"network_interfaces":[{"mac":"FE:FF:00:00:00:01"}]}`},
+		{"POST", "/accounts/myaccount/dhcp_reservations", 0, reservation},
+		{"GET", "/accounts/myaccount/dhcp_reservations/6", 0, reservation},
+		{"PUT", "/accounts/myaccount/dhcp_reservations/6", 0, reservation},
+		{"GET", "/accounts/myaccount/dhcp_reservations/6", 0, reservation},
+		{"DELETE", "/accounts/myaccount/dhcp_reservations/6", http.StatusNoContent, ""},
+	})
+
+	expected := map[string]interface{}{
+		"mac_address":    "fe:ff:00:00:00:01",
+		"ip_address":     "10.0.0.5",
+		"hostname":       "",
+		"lease_duration": float64(86400),
+	}
+	testBigvSent(t, "create", sent[1], expected)
+	testBigvSent(t, "update", sent[3], expected)
+
+	if d.Id() != "6" || d.Get("mac_address") != "fe:ff:00:00:00:01" {
+		t.Errorf("expected reservation 6 for fe:ff:00:00:00:01, got %s for %v", d.Id(), d.Get("mac_address"))
+	}
+}
+
+func TestDhcpReservationNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvDhcpReservation(), map[string]interface{}{"ip_address": "10.0.0.5"}, "6")
+}
@@ -0,0 +1,158 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceBigvDisc() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvDiscCreate,
+		Read:   resourceBigvDiscRead,
+		Update: resourceBigvDiscUpdate,
+		Delete: resourceBigvDiscDelete,
+
+		CustomizeDiff: resourceBigvDiscCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the VM to add the disc to",
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The VM's group. Defaults to the provider's default_group",
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"size": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Disc size in MiB. Discs can only grow, shrinking one recreates it",
+			},
+			"storage_grade": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultStorageGrade,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(storageGrades, false),
+			},
+			"provisioned_iops": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// resourceBigvDiscCustomizeDiff
+// bigv can grow discs in place, but not shrink them
+func resourceBigvDiscCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.Id() == "" || !d.HasChange("size") {
+		return nil
+	}
+
+	o, n := d.GetChange("size")
+	if n.(int) < o.(int) {
+		log.Println("[WARN] Warning: shrinking a disc will destroy and recreate it. All data on it will be lost.")
+		return d.ForceNew("size")
+	}
+
+	return nil
+}
+
+func bigvDiscsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s/discs",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Get("vm_id"),
+	)
+}
+
+func resourceBigvDiscCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset group is filled in here
+	if d.Get("group").(string) == "" {
+		d.Set("group", bigvClient.defaultGroup)
+	}
+
+	disc := &bigvDisc{}
+	_, err := bigvClient.doJson("POST", bigvDiscsUrl(d, bigvClient), bigvDisc{
+		Label:        d.Get("label").(string),
+		Size:         d.Get("size").(int),
+		StorageGrade: d.Get("storage_grade").(string),
+	}, disc)
+	if err != nil {
+		return err
+	}
+
+	if disc.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for disc %s", d.Get("label"))
+	}
+
+	d.SetId(strconv.Itoa(disc.Id))
+
+	log.Printf("[DEBUG] Created BigV Disc, Id: %s", d.Id())
+
+	return resourceBigvDiscRead(d, meta)
+}
+
+func resourceBigvDiscRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	disc := &bigvDisc{}
+	status, err := bigvClient.doJson("GET", bigvDiscsUrl(d, bigvClient)+"/"+d.Id(), nil, disc)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Disc %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("label", disc.Label)
+	d.Set("size", disc.Size)
+	d.Set("storage_grade", disc.StorageGrade)
+	d.Set("provisioned_iops", disc.ProvisionedIops)
+
+	return nil
+}
+
+// resourceBigvDiscUpdate
+// Grows the disc, the filesystem on it still needs growing from inside the VM
+func resourceBigvDiscUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvDiscsUrl(d, bigvClient)+"/"+d.Id(), bigvDisc{
+		Size: d.Get("size").(int),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvDiscRead(d, meta)
+}
+
+func resourceBigvDiscDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvDiscsUrl(d, bigvClient)+"/"+d.Id()+"?purge=true", nil, nil)
+	return err
+}
@@ -0,0 +1,36 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestDiscCreateInDefaultGroup(t *testing.T) {
+	var paths []string
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		w.Write([]byte(`{"id":6,"label":"data","storage_grade":"sata","size":102400}`))
+	})
+	defer server.Close()
+
+	c.defaultGroup = "staging"
+
+	d := schema.TestResourceDataRaw(t, resourceBigvDisc().Schema, map[string]interface{}{
+		"vm_id": "1",
+		"label": "data",
+		"size":  102400,
+	})
+
+	if err := resourceBigvDiscCreate(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 || paths[0] != "POST /accounts/myaccount/groups/staging/virtual_machines/1/discs" {
+		t.Fatalf("expected the disc to be created in the provider's default group, got %v", paths)
+	}
+	if d.Get("group") != "staging" {
+		t.Fatalf("expected group staging in state, got %v", d.Get("group"))
+	}
+}
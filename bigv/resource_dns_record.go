@@ -0,0 +1,254 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var dnsRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT"}
+
+type bigvDnsRecord struct {
+	Id      int    `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Ttl     int    `json:"ttl,omitempty"`
+	Content string `json:"content,omitempty"`
+}
+
+func resourceBigvDnsRecord() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvDnsRecordCreate,
+		Read:   resourceBigvDnsRecordRead,
+		Update: resourceBigvDnsRecordUpdate,
+		Delete: resourceBigvDnsRecordDelete,
+
+		CustomizeDiff: resourceBigvDnsRecordCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"zone": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The domain of the DNS zone the record is in, e.g. example.com",
+			},
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The record's name within the zone, or @ for the zone itself",
+			},
+			"type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(dnsRecordTypes, false),
+			},
+			"ttl": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validation.IntAtLeast(60),
+			},
+			"value": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"vm_id"},
+				Description:   "The record's content. MX records include their priority, e.g. 10 mail.example.com",
+			},
+			"vm_id": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"value"},
+				Description:   "A VM whose ip to use for A and AAAA records, instead of value",
+			},
+		},
+	}
+}
+
+// resourceBigvDnsRecordCustomizeDiff
+// Records following a VM need updating whenever its ip changes
+func resourceBigvDnsRecordCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	recordType := d.Get("type").(string)
+	vmId := d.Get("vm_id").(string)
+
+	if !d.NewValueKnown("vm_id") {
+		return d.SetNewComputed("value")
+	}
+	if vmId == "" {
+		// e.g. from a resource that's yet to be created, or left unset, which the apply catches
+		if !d.NewValueKnown("value") {
+			return nil
+		}
+		if _, ok := d.GetOk("value"); !ok {
+			return fmt.Errorf("One of value or vm_id must be set")
+		}
+		return nil
+	}
+
+	if recordType != "A" && recordType != "AAAA" {
+		return fmt.Errorf("vm_id can only be used for A and AAAA records, not %s", recordType)
+	}
+
+	ip, err := dnsRecordVMIp(bigvClient, vmId, recordType)
+	if err != nil {
+		return err
+	}
+
+	if ip != d.Get("value").(string) {
+		return d.SetNew("value", ip)
+	}
+
+	return nil
+}
+
+// dnsRecordVMIp
+// The ip for an A or AAAA record from the VM's first network interface
+func dnsRecordVMIp(bigvClient *client, vmId, recordType string) (string, error) {
+	url := fmt.Sprintf("%s/virtual_machines/%s?view=overview",
+		bigvClient.apiUri(),
+		vmId,
+	)
+
+	vm := &bigvServer{}
+	if _, err := bigvClient.doJson("GET", url, nil, vm); err != nil {
+		return "", err
+	}
+
+	if len(vm.Nics) == 0 {
+		return "", fmt.Errorf("VM %s has no network interfaces to take an ip from", vmId)
+	}
+
+	ipv4, ipv6 := vm.Nics[0].splitIps()
+	ip := ipv4
+	if recordType == "AAAA" {
+		ip = ipv6
+	}
+
+	if ip == "" {
+		return "", fmt.Errorf("VM %s has no ip for a %s record", vmId, recordType)
+	}
+
+	return ip, nil
+}
+
+func bigvDnsRecordsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/dns_zones/%s/records",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("zone"),
+	)
+}
+
+// dnsRecordFromResource
+// VMs not known at plan time only get their ip looked up now
+func dnsRecordFromResource(d *schema.ResourceData, bigvClient *client) (bigvDnsRecord, error) {
+	record := bigvDnsRecord{
+		Name:    d.Get("name").(string),
+		Type:    d.Get("type").(string),
+		Ttl:     d.Get("ttl").(int),
+		Content: d.Get("value").(string),
+	}
+
+	if vmId := d.Get("vm_id").(string); vmId != "" {
+		ip, err := dnsRecordVMIp(bigvClient, vmId, record.Type)
+		if err != nil {
+			return record, err
+		}
+		record.Content = ip
+	}
+
+	// Plans can't tell an unset value from one that isn't known yet, so this is the first we know of it
+	if record.Content == "" {
+		return record, fmt.Errorf("One of value or vm_id must be set")
+	}
+
+	return record, nil
+}
+
+func resourceBigvDnsRecordCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Otherwise all we'd get back is a 404 for the records
+	zone, err := readBigvDnsZone(bigvClient, d.Get("zone").(string))
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		return fmt.Errorf("bigv doesn't host a DNS zone for %s, it needs making first, e.g. with bigv_dns_zone", d.Get("zone"))
+	}
+
+	create, err := dnsRecordFromResource(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	record := &bigvDnsRecord{}
+	if _, err := bigvClient.doJson("POST", bigvDnsRecordsUrl(d, bigvClient), create, record); err != nil {
+		return err
+	}
+
+	if record.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the %s record %s", create.Type, create.Name)
+	}
+
+	d.SetId(strconv.Itoa(record.Id))
+
+	log.Printf("[DEBUG] Created BigV DNS record, Id: %s", d.Id())
+
+	return resourceBigvDnsRecordRead(d, meta)
+}
+
+func resourceBigvDnsRecordRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	record := &bigvDnsRecord{}
+	status, err := bigvClient.doJson("GET", bigvDnsRecordsUrl(d, bigvClient)+"/"+d.Id(), nil, record)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] DNS record %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", record.Name)
+	d.Set("type", record.Type)
+	d.Set("ttl", record.Ttl)
+	d.Set("value", record.Content)
+
+	return nil
+}
+
+func resourceBigvDnsRecordUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	update, err := dnsRecordFromResource(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	record := &bigvDnsRecord{}
+	if _, err := bigvClient.doJson("PUT", bigvDnsRecordsUrl(d, bigvClient)+"/"+d.Id(), update, record); err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Updated BigV DNS record, Id: %s", d.Id())
+
+	return resourceBigvDnsRecordRead(d, meta)
+}
+
+func resourceBigvDnsRecordDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvDnsRecordsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,91 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/hil/ast"
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testDnsRecordPlan
+// What terraform plan shows for a new record with the config, where var.address isn't known yet
+func testDnsRecordPlan(t *testing.T, c *client, raw map[string]interface{}) (*terraform.InstanceDiff, error) {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = rc.Interpolate(map[string]ast.Variable{
+		"var.address": ast.Variable{Type: ast.TypeUnknown, Value: config.UnknownVariableValue},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resourceBigvDnsRecord().Diff(nil, terraform.NewResourceConfig(rc), c)
+}
+
+func TestDnsRecordPlanUnknownValue(t *testing.T) {
+	diff, err := testDnsRecordPlan(t, &client{}, map[string]interface{}{
+		"zone":  "example.com",
+		"name":  "www",
+		"type":  "A",
+		"value": "${var.address}",
+	})
+	if err != nil {
+		t.Fatalf("expected a value only known at apply to be fine, got %s", err)
+	}
+	if !diff.Attributes["value"].NewComputed {
+		t.Fatalf("expected value to be known after apply, got %#v", diff.Attributes["value"])
+	}
+}
+
+func TestDnsRecordNeedsValue(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceBigvDnsRecord().Schema, map[string]interface{}{
+		"zone": "example.com",
+		"name": "www",
+		"type": "A",
+	})
+
+	if _, err := dnsRecordFromResource(d, &client{}); err == nil {
+		t.Fatal("expected an error without value or vm_id")
+	}
+}
+
+func TestDnsRecordPlanFollowsVM(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/virtual_machines/1" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"id":1,"network_interfaces":[{"ips":["2001:db8::10","192.0.2.10"]}]}`))
+	})
+	defer server.Close()
+
+	for recordType, ip := range map[string]string{"A": "192.0.2.10", "AAAA": "2001:db8::10"} {
+		diff, err := testDnsRecordPlan(t, c, map[string]interface{}{
+			"zone":  "example.com",
+			"name":  "www",
+			"type":  recordType,
+			"vm_id": "1",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if value := diff.Attributes["value"].New; value != ip {
+			t.Errorf("%s: expected %s, got %s", recordType, ip, value)
+		}
+	}
+
+	_, err := testDnsRecordPlan(t, c, map[string]interface{}{
+		"zone":  "example.com",
+		"name":  "www",
+		"type":  "CNAME",
+		"vm_id": "1",
+	})
+	if err == nil {
+		t.Fatal("expected vm_id to be refused for a CNAME")
+	}
+}
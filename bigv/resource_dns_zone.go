@@ -0,0 +1,161 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvDnsZone struct {
+	Domain       string `json:"domain,omitempty"`
+	Ttl          int    `json:"ttl,omitempty"`
+	ContactEmail string `json:"contact_email,omitempty"`
+
+	// Read Attributes
+	Nameservers []string `json:"nameservers,omitempty"`
+}
+
+func resourceBigvDnsZone() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvDnsZoneCreate,
+		Read:   resourceBigvDnsZoneRead,
+		Update: resourceBigvDnsZoneUpdate,
+		Delete: resourceBigvDnsZoneDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"domain": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The domain to host, e.g. example.com",
+			},
+			"ttl": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3600,
+				ValidateFunc: validation.IntAtLeast(60),
+				Description:  "The default TTL for the zone's records",
+			},
+			"contact_email": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The zone's SOA contact",
+			},
+			"nameservers": &schema.Schema{
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+// bigvDnsZoneUrl
+// Zones are known by their domain, which is also how records find them
+func bigvDnsZoneUrl(bigvClient *client, domain string) string {
+	return fmt.Sprintf("%s/accounts/%s/dns_zones/%s",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		domain,
+	)
+}
+
+func resourceBigvDnsZoneCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := fmt.Sprintf("%s/accounts/%s/dns_zones",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+
+	zone := &bigvDnsZone{}
+	_, err := bigvClient.doJson("POST", url, bigvDnsZone{
+		Domain:       d.Get("domain").(string),
+		Ttl:          d.Get("ttl").(int),
+		ContactEmail: d.Get("contact_email").(string),
+	}, zone)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(d.Get("domain").(string))
+
+	log.Printf("[DEBUG] Created BigV DNS zone %s", d.Id())
+
+	return resourceBigvDnsZoneRead(d, meta)
+}
+
+func resourceBigvDnsZoneRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	zone, err := readBigvDnsZone(bigvClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if zone == nil {
+		log.Printf("[WARN] DNS zone %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	dnsZoneToResource(d, zone)
+
+	return nil
+}
+
+func dnsZoneToResource(d *schema.ResourceData, zone *bigvDnsZone) {
+	d.Set("domain", zone.Domain)
+	d.Set("ttl", zone.Ttl)
+	d.Set("contact_email", zone.ContactEmail)
+	d.Set("nameservers", zone.Nameservers)
+}
+
+// readBigvDnsZone
+// nil without an error when bigv doesn't host the domain
+func readBigvDnsZone(bigvClient *client, domain string) (*bigvDnsZone, error) {
+	zone := &bigvDnsZone{}
+	status, err := bigvClient.doJson("GET", bigvDnsZoneUrl(bigvClient, domain), nil, zone)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func resourceBigvDnsZoneUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvDnsZoneUrl(bigvClient, d.Id()), bigvDnsZone{
+		Ttl:          d.Get("ttl").(int),
+		ContactEmail: d.Get("contact_email").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvDnsZoneRead(d, meta)
+}
+
+// resourceBigvDnsZoneDelete
+// Takes all the zone's records with it
+func resourceBigvDnsZoneDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvDnsZoneUrl(bigvClient, d.Id()), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,218 @@
+package bigv
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvFirewallRule struct {
+	Id              int    `json:"id,omitempty"`
+	Direction       string `json:"direction,omitempty"`
+	Protocol        string `json:"protocol,omitempty"`
+	PortRange       string `json:"port_range,omitempty"`
+	SourceCidr      string `json:"source_cidr,omitempty"`
+	DestinationCidr string `json:"destination_cidr,omitempty"`
+	Action          string `json:"action,omitempty"`
+	Priority        int    `json:"priority,omitempty"`
+}
+
+var portRangeRegexp = regexp.MustCompile(`^[0-9]+(-[0-9]+)?$`)
+
+// firewallRuleSchema
+// The rule's own attributes, shared with the bigv_firewall_rules data source
+func firewallRuleSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"direction": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "inbound",
+			ValidateFunc: validation.StringInSlice([]string{"inbound", "outbound"}, false),
+		},
+		"protocol": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice([]string{"tcp", "udp", "icmp"}, false),
+		},
+		"port_range": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			ValidateFunc: validation.StringMatch(portRangeRegexp, "must be a port, e.g. 22, or a range, e.g. 8000-8080"),
+			Description:  "A port or range of ports, e.g. 8000-8080. All ports if not set",
+		},
+		"source_cidr": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "0.0.0.0/0",
+			ValidateFunc: validation.CIDRNetwork(0, 128),
+		},
+		"destination_cidr": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "0.0.0.0/0",
+			ValidateFunc: validation.CIDRNetwork(0, 128),
+		},
+		"action": &schema.Schema{
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      "allow",
+			ValidateFunc: validation.StringInSlice([]string{"allow", "deny"}, false),
+		},
+		"priority": &schema.Schema{
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Default:      100,
+			ValidateFunc: validation.IntBetween(1, 1000),
+			Description:  "Rules are matched lowest priority first",
+		},
+	}
+}
+
+func resourceBigvFirewallRule() *schema.Resource {
+	s := firewallRuleSchema()
+	s["vm_id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"group_id"},
+		Description:   "The VM the rule applies to",
+	}
+	s["group_id"] = &schema.Schema{
+		Type:          schema.TypeString,
+		Optional:      true,
+		ForceNew:      true,
+		ConflictsWith: []string{"vm_id"},
+		Description:   "The group the rule applies to, for all its VMs",
+	}
+
+	return &schema.Resource{
+		Create: resourceBigvFirewallRuleCreate,
+		Read:   resourceBigvFirewallRuleRead,
+		Update: resourceBigvFirewallRuleUpdate,
+		Delete: resourceBigvFirewallRuleDelete,
+
+		Schema: s,
+	}
+}
+
+// bigvFirewallRulesUrl
+// Rules belong to either a VM or a group
+func bigvFirewallRulesUrl(d *schema.ResourceData, bigvClient *client) (string, error) {
+	if vmId := d.Get("vm_id").(string); vmId != "" {
+		return fmt.Sprintf("%s/virtual_machines/%s/firewall_rules", bigvClient.apiUri(), vmId), nil
+	}
+	if groupId := d.Get("group_id").(string); groupId != "" {
+		return fmt.Sprintf("%s/groups/%s/firewall_rules", bigvClient.apiUri(), groupId), nil
+	}
+
+	return "", errors.New("One of vm_id or group_id must be set")
+}
+
+func firewallRuleFromResource(d *schema.ResourceData) bigvFirewallRule {
+	return bigvFirewallRule{
+		Direction:       d.Get("direction").(string),
+		Protocol:        d.Get("protocol").(string),
+		PortRange:       d.Get("port_range").(string),
+		SourceCidr:      d.Get("source_cidr").(string),
+		DestinationCidr: d.Get("destination_cidr").(string),
+		Action:          d.Get("action").(string),
+		Priority:        d.Get("priority").(int),
+	}
+}
+
+func resourceBigvFirewallRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url, err := bigvFirewallRulesUrl(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	create := firewallRuleFromResource(d)
+	if create.Protocol == "icmp" && create.PortRange != "" {
+		return errors.New("icmp has no ports, so port_range can't be set for icmp rules")
+	}
+
+	rule := &bigvFirewallRule{}
+	if _, err := bigvClient.doJson("POST", url, create, rule); err != nil {
+		return err
+	}
+
+	if rule.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the firewall rule")
+	}
+
+	d.SetId(strconv.Itoa(rule.Id))
+
+	log.Printf("[DEBUG] Created BigV firewall rule, Id: %s", d.Id())
+
+	return resourceBigvFirewallRuleRead(d, meta)
+}
+
+func resourceBigvFirewallRuleRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url, err := bigvFirewallRulesUrl(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	rule := &bigvFirewallRule{}
+	status, err := bigvClient.doJson("GET", url+"/"+d.Id(), nil, rule)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Firewall rule %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("direction", rule.Direction)
+	d.Set("protocol", rule.Protocol)
+	d.Set("port_range", rule.PortRange)
+	d.Set("source_cidr", rule.SourceCidr)
+	d.Set("destination_cidr", rule.DestinationCidr)
+	d.Set("action", rule.Action)
+	d.Set("priority", rule.Priority)
+
+	return nil
+}
+
+func resourceBigvFirewallRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url, err := bigvFirewallRulesUrl(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	update := firewallRuleFromResource(d)
+	if update.Protocol == "icmp" && update.PortRange != "" {
+		return errors.New("icmp has no ports, so port_range can't be set for icmp rules")
+	}
+
+	if _, err := bigvClient.doJson("PUT", url+"/"+d.Id(), update, nil); err != nil {
+		return err
+	}
+
+	return resourceBigvFirewallRuleRead(d, meta)
+}
+
+func resourceBigvFirewallRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url, err := bigvFirewallRulesUrl(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	_, err = bigvClient.doJson("DELETE", url+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,188 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvFloatingIp struct {
+	Id      int    `json:"id,omitempty"`
+	Address string `json:"ip,omitempty"`
+	Zone    string `json:"zone_name,omitempty"`
+	VmId    int    `json:"virtual_machine_id,omitempty"`
+}
+
+func resourceBigvFloatingIp() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvFloatingIpCreate,
+		Read:   resourceBigvFloatingIpRead,
+		Update: resourceBigvFloatingIpUpdate,
+		Delete: resourceBigvFloatingIpDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"zone": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+				Description:  "The zone the address is for, which must match its VM's. Defaults to the provider's default_zone",
+			},
+			"vm_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The VM to route the address to. Unset takes it off any VM",
+			},
+			"release_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Give the address back to bigv on destroy, rather than keeping it reserved",
+			},
+			"address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvFloatingIpUrl(bigvClient *client, id string) string {
+	return fmt.Sprintf("%s/accounts/%s/floating_ips/%s",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		id,
+	)
+}
+
+func resourceBigvFloatingIpCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset zone is filled in here
+	if d.Get("zone").(string) == "" {
+		d.Set("zone", bigvClient.defaultZone)
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/floating_ips",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+
+	ip := &bigvFloatingIp{}
+	_, err := bigvClient.doJson("POST", url, bigvFloatingIp{
+		Zone: d.Get("zone").(string),
+	}, ip)
+	if err != nil {
+		return err
+	}
+
+	if ip.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the floating ip")
+	}
+
+	d.SetId(strconv.Itoa(ip.Id))
+
+	log.Printf("[DEBUG] Created BigV floating IP %s, Id: %s", ip.Address, d.Id())
+
+	if vmId := d.Get("vm_id").(string); vmId != "" {
+		if err := attachBigvFloatingIp(bigvClient, d.Id(), vmId); err != nil {
+			return err
+		}
+	}
+
+	return resourceBigvFloatingIpRead(d, meta)
+}
+
+func resourceBigvFloatingIpRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ip, err := readBigvFloatingIp(bigvClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if ip == nil {
+		log.Printf("[WARN] Floating IP %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("zone", ip.Zone)
+	d.Set("address", ip.Address)
+
+	vmId := ""
+	if ip.VmId != 0 {
+		vmId = strconv.Itoa(ip.VmId)
+	}
+	d.Set("vm_id", vmId)
+
+	return nil
+}
+
+// resourceBigvFloatingIpUpdate
+// Moves the address to another VM, or takes it off its VM when vm_id is emptied
+func resourceBigvFloatingIpUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if d.HasChange("vm_id") {
+		if err := attachBigvFloatingIp(bigvClient, d.Id(), d.Get("vm_id").(string)); err != nil {
+			return err
+		}
+	}
+
+	return resourceBigvFloatingIpRead(d, meta)
+}
+
+func resourceBigvFloatingIpDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if !d.Get("release_on_destroy").(bool) {
+		log.Printf("[WARN] Leaving floating ip %s reserved in bigv, it'll need releasing by hand", d.Get("address"))
+		return attachBigvFloatingIp(bigvClient, d.Id(), "")
+	}
+
+	_, err := bigvClient.doJson("DELETE", bigvFloatingIpUrl(bigvClient, d.Id()), nil, nil)
+	return err
+}
+
+// readBigvFloatingIp
+// nil without an error when bigv doesn't have it
+func readBigvFloatingIp(bigvClient *client, id string) (*bigvFloatingIp, error) {
+	ip := &bigvFloatingIp{}
+	status, err := bigvClient.doJson("GET", bigvFloatingIpUrl(bigvClient, id), nil, ip)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ip, nil
+}
+
+// attachBigvFloatingIp
+// Routes the address to the VM, or to nothing when vmId is empty
+func attachBigvFloatingIp(bigvClient *client, id, vmId string) error {
+	var attach interface{}
+	if vmId != "" {
+		vm, err := strconv.Atoi(vmId)
+		if err != nil {
+			return fmt.Errorf("vm_id must be a numeric VM id, got: %s", vmId)
+		}
+		attach = vm
+	}
+
+	log.Printf("[DEBUG] Attaching BigV floating IP %s to VM %q", id, vmId)
+
+	_, err := bigvClient.doJson("PUT", bigvFloatingIpUrl(bigvClient, id), map[string]interface{}{
+		"virtual_machine_id": attach,
+	}, nil)
+	return err
+}
@@ -0,0 +1,80 @@
+package bigv
+
+import (
+	"log"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// resourceBigvFloatingIpAttachment
+// For VMs using the floating ip as their own ipv4 address, where vm_id on the
+// bigv_floating_ip would make a cycle.
+func resourceBigvFloatingIpAttachment() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvFloatingIpAttachmentCreate,
+		Read:   resourceBigvFloatingIpAttachmentRead,
+		Delete: resourceBigvFloatingIpAttachmentDelete,
+
+		Schema: map[string]*schema.Schema{
+			"floating_ip_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceBigvFloatingIpAttachmentCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	id := d.Get("floating_ip_id").(string)
+	if err := attachBigvFloatingIp(bigvClient, id, d.Get("vm_id").(string)); err != nil {
+		return err
+	}
+
+	d.SetId(id)
+
+	return resourceBigvFloatingIpAttachmentRead(d, meta)
+}
+
+// resourceBigvFloatingIpAttachmentRead
+// Moved to another VM counts as gone
+func resourceBigvFloatingIpAttachmentRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ip, err := readBigvFloatingIp(bigvClient, d.Id())
+	if err != nil {
+		return err
+	}
+
+	if ip == nil || strconv.Itoa(ip.VmId) != d.Get("vm_id").(string) {
+		log.Printf("[WARN] Floating IP %s no longer attached to VM %s, removing from state", d.Id(), d.Get("vm_id"))
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+func resourceBigvFloatingIpAttachmentDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ip, err := readBigvFloatingIp(bigvClient, d.Id())
+	if err != nil || ip == nil {
+		return err
+	}
+
+	// Don't take it off a VM something else has since moved it to
+	if strconv.Itoa(ip.VmId) != d.Get("vm_id").(string) {
+		return nil
+	}
+
+	return attachBigvFloatingIp(bigvClient, d.Id(), "")
+}
@@ -0,0 +1,81 @@
+package bigv
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestFloatingIpCreateAttaches(t *testing.T) {
+	var requests []string
+	var created bigvFloatingIp
+	var attach map[string]interface{}
+
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+
+		switch r.Method {
+		case "POST":
+			json.NewDecoder(r.Body).Decode(&created)
+			w.Write([]byte(`{"id":4,"ip":"192.0.2.50","zone_name":"york"}`))
+		case "PUT":
+			json.NewDecoder(r.Body).Decode(&attach)
+		default:
+			w.Write([]byte(`{"id":4,"ip":"192.0.2.50","zone_name":"york","virtual_machine_id":1}`))
+		}
+	})
+	defer server.Close()
+
+	c.defaultZone = "manchester"
+
+	d := schema.TestResourceDataRaw(t, resourceBigvFloatingIp().Schema, map[string]interface{}{
+		"vm_id": "1",
+	})
+
+	if err := resourceBigvFloatingIpCreate(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /accounts/myaccount/floating_ips",
+		"PUT /accounts/myaccount/floating_ips/4",
+		"GET /accounts/myaccount/floating_ips/4",
+	}
+	if len(requests) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, requests)
+	}
+	for i := range expected {
+		if requests[i] != expected[i] {
+			t.Errorf("request %d: expected %s, got %s", i, expected[i], requests[i])
+		}
+	}
+
+	if created.Zone != "manchester" {
+		t.Errorf("expected the address in the provider's default zone, got %q", created.Zone)
+	}
+	if attach["virtual_machine_id"] != float64(1) {
+		t.Errorf("expected it attached to VM 1, got %v", attach)
+	}
+	if d.Id() != "4" || d.Get("address") != "192.0.2.50" || d.Get("vm_id") != "1" {
+		t.Errorf("expected floating ip 4 on VM 1, got %s %s on %s", d.Id(), d.Get("address"), d.Get("vm_id"))
+	}
+}
+
+func TestFloatingIpDetach(t *testing.T) {
+	var attach map[string]interface{}
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&attach)
+	})
+	defer server.Close()
+
+	if err := attachBigvFloatingIp(c, "4", ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Sent as null, so bigv takes it off the VM
+	if v, ok := attach["virtual_machine_id"]; !ok || v != nil {
+		t.Fatalf("expected a null virtual_machine_id, got %v", attach)
+	}
+}
@@ -0,0 +1,150 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvGroup struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Not omitempty, so that updates can clear it
+	BillingCode string `json:"billing_code"`
+
+	// Read Attributes
+	VirtualMachines []bigvVm `json:"virtual_machines,omitempty"`
+}
+
+func resourceBigvGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvGroupCreate,
+		Read:   resourceBigvGroupRead,
+		Update: resourceBigvGroupUpdate,
+		Delete: resourceBigvGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"group_billing_code": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateBillingCode,
+				Description:  "Billing code for allocating the group's costs, inherited by its VMs",
+			},
+			"account": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vm_count": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvGroupsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/groups",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group := &bigvGroup{}
+	_, err := bigvClient.doJson("POST", bigvGroupsUrl(bigvClient), bigvGroup{
+		Name:        d.Get("name").(string),
+		BillingCode: d.Get("group_billing_code").(string),
+	}, group)
+	if err != nil {
+		return err
+	}
+
+	if group.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for group %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	log.Printf("[DEBUG] Created BigV Group, Id: %s", d.Id())
+
+	return resourceBigvGroupRead(d, meta)
+}
+
+func resourceBigvGroupRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group := &bigvGroup{}
+	status, err := bigvClient.doJson("GET", bigvGroupsUrl(bigvClient)+"/"+d.Id()+"?view=overview", nil, group)
+	if status == http.StatusNotFound {
+		// Deleted outside terraform, so it needs creating again
+		log.Printf("[WARN] Group %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	groupToResource(d, group)
+	d.Set("account", bigvClient.account)
+
+	return nil
+}
+
+// groupToResource
+// Everything bigv tells us about a group
+func groupToResource(d *schema.ResourceData, group *bigvGroup) {
+	d.Set("name", group.Name)
+	d.Set("group_billing_code", group.BillingCode)
+	d.Set("vm_count", len(group.VirtualMachines))
+}
+
+// resourceBigvGroupUpdate
+// Only the billing code can change, renaming would break every VM path using the name
+func resourceBigvGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvGroupsUrl(bigvClient)+"/"+d.Id(), bigvGroup{
+		BillingCode: d.Get("group_billing_code").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvGroupRead(d, meta)
+}
+
+func resourceBigvGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvGroupsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+var billingCodeRegexp = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+
+// validateBillingCode
+// Billing codes are alphanumeric, optionally with hyphens
+func validateBillingCode(v interface{}, k string) (ws []string, es []error) {
+	if !billingCodeRegexp.MatchString(v.(string)) {
+		es = append(es, fmt.Errorf("%s must only contain letters, numbers and hyphens, got: %s", k, v))
+	}
+	return
+}
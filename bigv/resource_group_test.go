@@ -0,0 +1,135 @@
+package bigv
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestValidateBillingCode(t *testing.T) {
+	valid := []string{"ops-123", "OPS", "12345", "cost-centre-7"}
+	for _, code := range valid {
+		if _, es := validateBillingCode(code, "group_billing_code"); len(es) > 0 {
+			t.Errorf("%s: expected it to be valid, got %s", code, es)
+		}
+	}
+
+	invalid := []string{"", "ops 123", "ops_123", "ops/123", "£100"}
+	for _, code := range invalid {
+		if _, es := validateBillingCode(code, "group_billing_code"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", code)
+		}
+	}
+}
+
+func TestGroupBillingCodeValidatedInPlan(t *testing.T) {
+	cases := []struct {
+		code  string
+		valid bool
+	}{
+		{"ops-123", true},
+		{"ops 123", false},
+		{"ops_123", false},
+	}
+
+	for _, tc := range cases {
+		rc, err := config.NewRawConfig(map[string]interface{}{"name": "staging", "group_billing_code": tc.code})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, es := resourceBigvGroup().Validate(terraform.NewResourceConfig(rc))
+		if tc.valid && len(es) > 0 {
+			t.Errorf("%q: expected it to be valid, got %s", tc.code, es)
+		}
+		if !tc.valid && len(es) == 0 {
+			t.Errorf("%q: expected it to fail validation", tc.code)
+		}
+	}
+}
+
+func TestGroupRoundTrip(t *testing.T) {
+	var requests []string
+	var sent []bigvGroup
+
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.RequestURI())
+
+		group := bigvGroup{}
+		json.NewDecoder(r.Body).Decode(&group)
+		if r.Method == "POST" || r.Method == "PUT" {
+			sent = append(sent, group)
+		}
+
+		switch r.Method {
+		case "DELETE":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.Write([]byte(`{"id":12,"name":"staging","billing_code":"ops-123","virtual_machines":[{"id":1},{"id":2}]}`))
+		}
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceBigvGroup().Schema, map[string]interface{}{
+		"name":               "staging",
+		"group_billing_code": "ops-123",
+	})
+
+	if err := resourceBigvGroupCreate(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := resourceBigvGroupUpdate(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if err := resourceBigvGroupDelete(d, c); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"POST /accounts/myaccount/groups",
+		"GET /accounts/myaccount/groups/12?view=overview",
+		"PUT /accounts/myaccount/groups/12",
+		"GET /accounts/myaccount/groups/12?view=overview",
+		"DELETE /accounts/myaccount/groups/12",
+	}
+	if strings.Join(requests, "\n") != strings.Join(expected, "\n") {
+		t.Fatalf("expected:\n%s\ngot:\n%s", strings.Join(expected, "\n"), strings.Join(requests, "\n"))
+	}
+
+	if sent[0].Name != "staging" || sent[0].BillingCode != "ops-123" {
+		t.Errorf("expected the group to be created with its name and billing code, got %#v", sent[0])
+	}
+	if sent[1].Name != "" || sent[1].BillingCode != "ops-123" {
+		t.Errorf("expected only the billing code to be updated, got %#v", sent[1])
+	}
+
+	if d.Id() != "12" || d.Get("vm_count") != 2 || d.Get("account") != "myaccount" {
+		t.Errorf("expected group 12 with 2 VMs in myaccount, got %s with %v in %v", d.Id(), d.Get("vm_count"), d.Get("account"))
+	}
+}
+
+func TestGroupNotFound(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceBigvGroup().Schema, map[string]interface{}{"name": "staging"})
+	d.SetId("12")
+
+	if err := resourceBigvGroupRead(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if d.Id() != "" {
+		t.Fatal("expected a deleted group to be removed from state")
+	}
+
+	if err := resourceBigvGroupDelete(d, c); err != nil {
+		t.Fatalf("expected deleting a missing group to succeed, got %s", err)
+	}
+}
@@ -0,0 +1,172 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvHaPair struct {
+	Id                int    `json:"id,omitempty"`
+	PrimaryVmId       int    `json:"primary_virtual_machine_id,omitempty"`
+	SecondaryVmId     int    `json:"secondary_virtual_machine_id,omitempty"`
+	FailoverIp        string `json:"failover_ip,omitempty"`
+	HealthCheckScript string `json:"health_check_script"`
+	AutoFailover      bool   `json:"auto_failover"`
+
+	// Read Attributes
+	ActiveVmId       int    `json:"active_virtual_machine_id,omitempty"`
+	LastFailoverTime string `json:"last_failover_at,omitempty"`
+}
+
+func resourceBigvHaPair() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvHaPairCreate,
+		Read:   resourceBigvHaPairRead,
+		Update: resourceBigvHaPairUpdate,
+		Delete: resourceBigvHaPairDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"primary_vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"secondary_vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"failover_ip": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIP,
+				Description:  "The shared address, which follows whichever VM is active",
+			},
+			"health_check_script": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Run on the primary to check its health, failing over on a non-zero exit",
+			},
+			"auto_failover": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"active_vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"last_failover_time": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvHaPairsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/ha_pairs",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvHaPairCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	primary, err := strconv.Atoi(d.Get("primary_vm_id").(string))
+	if err != nil {
+		return fmt.Errorf("primary_vm_id must be a numeric VM id, got: %s", d.Get("primary_vm_id"))
+	}
+	secondary, err := strconv.Atoi(d.Get("secondary_vm_id").(string))
+	if err != nil {
+		return fmt.Errorf("secondary_vm_id must be a numeric VM id, got: %s", d.Get("secondary_vm_id"))
+	}
+	if primary == secondary {
+		return fmt.Errorf("primary_vm_id and secondary_vm_id must be different VMs, both are %d", primary)
+	}
+
+	pair := &bigvHaPair{}
+	_, err = bigvClient.doJson("POST", bigvHaPairsUrl(bigvClient), bigvHaPair{
+		PrimaryVmId:       primary,
+		SecondaryVmId:     secondary,
+		FailoverIp:        d.Get("failover_ip").(string),
+		HealthCheckScript: d.Get("health_check_script").(string),
+		AutoFailover:      d.Get("auto_failover").(bool),
+	}, pair)
+	if err != nil {
+		return err
+	}
+
+	if pair.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the HA pair of VMs %d and %d", primary, secondary)
+	}
+
+	d.SetId(strconv.Itoa(pair.Id))
+
+	log.Printf("[DEBUG] Created BigV HA pair, Id: %s", d.Id())
+
+	return resourceBigvHaPairRead(d, meta)
+}
+
+func resourceBigvHaPairRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	pair := &bigvHaPair{}
+	status, err := bigvClient.doJson("GET", bigvHaPairsUrl(bigvClient)+"/"+d.Id(), nil, pair)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] HA pair %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	// The primary and secondary are what was configured, failovers only change the active VM
+	d.Set("primary_vm_id", strconv.Itoa(pair.PrimaryVmId))
+	d.Set("secondary_vm_id", strconv.Itoa(pair.SecondaryVmId))
+	d.Set("failover_ip", pair.FailoverIp)
+	d.Set("health_check_script", pair.HealthCheckScript)
+	d.Set("auto_failover", pair.AutoFailover)
+	d.Set("active_vm_id", strconv.Itoa(pair.ActiveVmId))
+	d.Set("last_failover_time", pair.LastFailoverTime)
+
+	return nil
+}
+
+func resourceBigvHaPairUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvHaPairsUrl(bigvClient)+"/"+d.Id(), bigvHaPair{
+		HealthCheckScript: d.Get("health_check_script").(string),
+		AutoFailover:      d.Get("auto_failover").(bool),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvHaPairRead(d, meta)
+}
+
+// resourceBigvHaPairDelete
+// Dissolves the pairing, both VMs are left running. The failover ip goes back to the account
+func resourceBigvHaPairDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvHaPairsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
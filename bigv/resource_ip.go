@@ -0,0 +1,139 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvIpReservation struct {
+	Id      int    `json:"id,omitempty"`
+	Version int    `json:"version,omitempty"`
+	Ip      string `json:"ip,omitempty"`
+	Zone    string `json:"zone_name,omitempty"`
+}
+
+func resourceBigvIp() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvIpCreate,
+		Read:   resourceBigvIpRead,
+		Update: resourceBigvIpUpdate,
+		Delete: resourceBigvIpDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"version": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      4,
+				ForceNew:     true,
+				ValidateFunc: validation.IntInSlice([]int{4, 6}),
+				Description:  "4 for an ipv4 address, or 6 for ipv6",
+			},
+			"zone": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+				Description:  "The zone the address is for, which must match its VM's. Defaults to the provider's default_zone",
+			},
+			"release_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Give the address back to bigv on destroy, rather than keeping it reserved",
+			},
+			"ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvIpUrl(bigvClient *client, id string) string {
+	return fmt.Sprintf("%s/accounts/%s/ips/%s",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		id,
+	)
+}
+
+func resourceBigvIpCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset zone is filled in here
+	if d.Get("zone").(string) == "" {
+		d.Set("zone", bigvClient.defaultZone)
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/ips",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+
+	ip := &bigvIpReservation{}
+	_, err := bigvClient.doJson("POST", url, bigvIpReservation{
+		Version: d.Get("version").(int),
+		Zone:    d.Get("zone").(string),
+	}, ip)
+	if err != nil {
+		return err
+	}
+
+	if ip.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the ip reservation")
+	}
+
+	d.SetId(strconv.Itoa(ip.Id))
+
+	log.Printf("[DEBUG] Reserved BigV IP %s, Id: %s", ip.Ip, d.Id())
+
+	return resourceBigvIpRead(d, meta)
+}
+
+func resourceBigvIpRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ip := &bigvIpReservation{}
+	status, err := bigvClient.doJson("GET", bigvIpUrl(bigvClient, d.Id()), nil, ip)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] IP reservation %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("version", ip.Version)
+	d.Set("zone", ip.Zone)
+	d.Set("ip", ip.Ip)
+
+	return nil
+}
+
+// resourceBigvIpUpdate
+// Only release_on_destroy can change, and that's just in terraform
+func resourceBigvIpUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceBigvIpRead(d, meta)
+}
+
+func resourceBigvIpDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if !d.Get("release_on_destroy").(bool) {
+		log.Printf("[WARN] Leaving ip %s reserved in bigv, it'll need releasing by hand", d.Get("ip"))
+		return nil
+	}
+
+	_, err := bigvClient.doJson("DELETE", bigvIpUrl(bigvClient, d.Id()), nil, nil)
+	return err
+}
@@ -0,0 +1,138 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvIpPool struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	Cidr string `json:"cidr,omitempty"`
+
+	// Read Attributes
+	TotalIps     int `json:"total_ips,omitempty"`
+	UsedIps      int `json:"used_ips,omitempty"`
+	AvailableIps int `json:"available_ips,omitempty"`
+}
+
+func resourceBigvIpPool() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvIpPoolCreate,
+		Read:   resourceBigvIpPoolRead,
+		Delete: resourceBigvIpPoolDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name VMs allocate from the pool by in ip_pool",
+			},
+			"cidr": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.CIDRNetwork(0, 128),
+				Description:  "The block of addresses in the pool, e.g. 192.0.2.0/28",
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Only the group's VMs can use the pool. Any of the account's can without this",
+			},
+			"total_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"used_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"available_ips": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// bigvIpPoolsUrl
+// Pools belong to a group, or to the whole account
+func bigvIpPoolsUrl(d *schema.ResourceData, bigvClient *client) string {
+	if group := d.Get("group").(string); group != "" {
+		return fmt.Sprintf("%s/accounts/%s/groups/%s/ip_pools",
+			bigvClient.apiUri(),
+			bigvClient.account,
+			group,
+		)
+	}
+
+	return fmt.Sprintf("%s/accounts/%s/ip_pools",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvIpPoolCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	pool := &bigvIpPool{}
+	_, err := bigvClient.doJson("POST", bigvIpPoolsUrl(d, bigvClient), bigvIpPool{
+		Name: d.Get("name").(string),
+		Cidr: d.Get("cidr").(string),
+	}, pool)
+	if err != nil {
+		return err
+	}
+
+	if pool.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for ip pool %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(pool.Id))
+
+	log.Printf("[DEBUG] Created BigV IP pool, Id: %s", d.Id())
+
+	return resourceBigvIpPoolRead(d, meta)
+}
+
+func resourceBigvIpPoolRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	pool := &bigvIpPool{}
+	status, err := bigvClient.doJson("GET", bigvIpPoolsUrl(d, bigvClient)+"/"+d.Id(), nil, pool)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] IP pool %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ipPoolToResource(d, pool)
+
+	return nil
+}
+
+func ipPoolToResource(d *schema.ResourceData, pool *bigvIpPool) {
+	d.Set("name", pool.Name)
+	d.Set("cidr", pool.Cidr)
+	d.Set("total_ips", pool.TotalIps)
+	d.Set("used_ips", pool.UsedIps)
+	d.Set("available_ips", pool.AvailableIps)
+}
+
+func resourceBigvIpPoolDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvIpPoolsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
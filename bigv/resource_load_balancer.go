@@ -0,0 +1,261 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvLoadBalancer struct {
+	Id        int    `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+	Port      int    `json:"port,omitempty"`
+
+	HealthCheck bigvHealthCheck `json:"health_check"`
+	Backends    []bigvBackend   `json:"backends"`
+
+	// Read Attributes
+	IpAddress string `json:"ip,omitempty"`
+}
+
+type bigvHealthCheck struct {
+	Protocol           string `json:"protocol,omitempty"`
+	Path               string `json:"path,omitempty"`
+	Interval           int    `json:"interval,omitempty"`
+	HealthyThreshold   int    `json:"healthy_threshold,omitempty"`
+	UnhealthyThreshold int    `json:"unhealthy_threshold,omitempty"`
+}
+
+type bigvBackend struct {
+	VmId int `json:"virtual_machine_id"`
+	Port int `json:"port"`
+}
+
+func resourceBigvLoadBalancer() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvLoadBalancerCreate,
+		Read:   resourceBigvLoadBalancerRead,
+		Update: resourceBigvLoadBalancerUpdate,
+		Delete: resourceBigvLoadBalancerDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"algorithm": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "round_robin",
+				ValidateFunc: validation.StringInSlice([]string{"round_robin", "least_connections", "ip_hash"}, false),
+			},
+			"protocol": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "http",
+				ValidateFunc: validation.StringInSlice([]string{"http", "https", "tcp"}, false),
+			},
+			"port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+				Description:  "The port the load balancer listens on",
+			},
+			"health_check_protocol": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "http",
+				ValidateFunc: validation.StringInSlice([]string{"http", "tcp"}, false),
+				Description:  "http checks for a 2xx from health_check_path, tcp just for the port accepting connections",
+			},
+			"health_check_path": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  "/",
+			},
+			"health_check_interval": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 300),
+				Description:  "Seconds between checks on each backend",
+			},
+			"healthy_threshold": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Checks in a row a backend has to pass to be sent traffic",
+			},
+			"unhealthy_threshold": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      3,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Checks in a row a backend has to fail to stop being sent traffic",
+			},
+			"backend": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"vm_id": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"port": &schema.Schema{
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntBetween(1, 65535),
+						},
+					},
+				},
+			},
+			"ip_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvLoadBalancersUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/load_balancers",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func loadBalancerFromResource(d *schema.ResourceData) (bigvLoadBalancer, error) {
+	lb := bigvLoadBalancer{
+		Name:      d.Get("name").(string),
+		Algorithm: d.Get("algorithm").(string),
+		Protocol:  d.Get("protocol").(string),
+		Port:      d.Get("port").(int),
+		HealthCheck: bigvHealthCheck{
+			Protocol:           d.Get("health_check_protocol").(string),
+			Interval:           d.Get("health_check_interval").(int),
+			HealthyThreshold:   d.Get("healthy_threshold").(int),
+			UnhealthyThreshold: d.Get("unhealthy_threshold").(int),
+		},
+		Backends: []bigvBackend{},
+	}
+
+	// tcp checks have no path to ask for
+	if lb.HealthCheck.Protocol == "http" {
+		lb.HealthCheck.Path = d.Get("health_check_path").(string)
+	}
+
+	for _, v := range d.Get("backend").([]interface{}) {
+		backend := v.(map[string]interface{})
+
+		vmId, err := strconv.Atoi(backend["vm_id"].(string))
+		if err != nil {
+			return lb, fmt.Errorf("backend vm_id must be a numeric VM id, got: %s", backend["vm_id"])
+		}
+
+		lb.Backends = append(lb.Backends, bigvBackend{
+			VmId: vmId,
+			Port: backend["port"].(int),
+		})
+	}
+
+	return lb, nil
+}
+
+func resourceBigvLoadBalancerCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	create, err := loadBalancerFromResource(d)
+	if err != nil {
+		return err
+	}
+
+	lb := &bigvLoadBalancer{}
+	if _, err := bigvClient.doJson("POST", bigvLoadBalancersUrl(bigvClient), create, lb); err != nil {
+		return err
+	}
+
+	if lb.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for load balancer %s", create.Name)
+	}
+
+	d.SetId(strconv.Itoa(lb.Id))
+
+	log.Printf("[DEBUG] Created BigV load balancer, Id: %s", d.Id())
+
+	return resourceBigvLoadBalancerRead(d, meta)
+}
+
+func resourceBigvLoadBalancerRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	lb := &bigvLoadBalancer{}
+	status, err := bigvClient.doJson("GET", bigvLoadBalancersUrl(bigvClient)+"/"+d.Id(), nil, lb)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Load balancer %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", lb.Name)
+	d.Set("algorithm", lb.Algorithm)
+	d.Set("protocol", lb.Protocol)
+	d.Set("port", lb.Port)
+	d.Set("health_check_protocol", lb.HealthCheck.Protocol)
+	d.Set("health_check_interval", lb.HealthCheck.Interval)
+	d.Set("healthy_threshold", lb.HealthCheck.HealthyThreshold)
+	d.Set("unhealthy_threshold", lb.HealthCheck.UnhealthyThreshold)
+	d.Set("ip_address", lb.IpAddress)
+
+	// Keep whatever path was configured for tcp checks, which don't have one
+	if lb.HealthCheck.Protocol == "http" {
+		d.Set("health_check_path", lb.HealthCheck.Path)
+	}
+
+	backends := make([]interface{}, len(lb.Backends))
+	for i, backend := range lb.Backends {
+		backends[i] = map[string]interface{}{
+			"vm_id": strconv.Itoa(backend.VmId),
+			"port":  backend.Port,
+		}
+	}
+	d.Set("backend", backends)
+
+	return nil
+}
+
+func resourceBigvLoadBalancerUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	update, err := loadBalancerFromResource(d)
+	if err != nil {
+		return err
+	}
+
+	if _, err := bigvClient.doJson("PUT", bigvLoadBalancersUrl(bigvClient)+"/"+d.Id(), update, nil); err != nil {
+		return err
+	}
+
+	return resourceBigvLoadBalancerRead(d, meta)
+}
+
+func resourceBigvLoadBalancerDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvLoadBalancersUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,195 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvLogDrain struct {
+	Id             int    `json:"id,omitempty"`
+	DestinationUrl string `json:"destination_url,omitempty"`
+	FilterPattern  string `json:"filter_pattern"`
+	DrainType      string `json:"drain_type,omitempty"`
+	Enabled        bool   `json:"enabled"`
+}
+
+type bigvLogDrainMetrics struct {
+	MessagesForwarded int `json:"messages_forwarded"`
+}
+
+// The url schemes each drain type can send to
+var logDrainSchemes = map[string][]string{
+	"syslog": []string{"syslog", "syslog+tcp", "syslog+tls"},
+	"http":   []string{"http", "https"},
+}
+
+func resourceBigvLogDrain() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvLogDrainCreate,
+		Read:   resourceBigvLogDrainRead,
+		Update: resourceBigvLogDrainUpdate,
+		Delete: resourceBigvLogDrainDelete,
+
+		CustomizeDiff: resourceBigvLogDrainCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"destination_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Where to send logs, e.g. syslog+tls://logs.example.com:6514",
+			},
+			"filter_pattern": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only forward log lines matching this regular expression",
+			},
+			"drain_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "syslog",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"syslog", "http"}, false),
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+			"messages_forwarded": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "How many log lines the drain has forwarded",
+			},
+		},
+	}
+}
+
+// resourceBigvLogDrainCustomizeDiff
+// The destination's scheme has to suit the drain type
+func resourceBigvLogDrainCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if !d.NewValueKnown("destination_url") || !d.NewValueKnown("drain_type") {
+		return nil
+	}
+
+	destination := d.Get("destination_url").(string)
+	drainType := d.Get("drain_type").(string)
+
+	u, err := url.Parse(destination)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("destination_url must be a url such as syslog+tls://logs.example.com:6514, got: %s", destination)
+	}
+
+	for _, scheme := range logDrainSchemes[drainType] {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("destination_url for a %s drain must use one of %v, got: %s", drainType, logDrainSchemes[drainType], u.Scheme)
+}
+
+func bigvLogDrainsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/log_drains",
+		bigvClient.apiUri(),
+		d.Get("vm_id"),
+	)
+}
+
+func logDrainFromResource(d *schema.ResourceData) bigvLogDrain {
+	return bigvLogDrain{
+		DestinationUrl: d.Get("destination_url").(string),
+		FilterPattern:  d.Get("filter_pattern").(string),
+		DrainType:      d.Get("drain_type").(string),
+		Enabled:        d.Get("enabled").(bool),
+	}
+}
+
+func resourceBigvLogDrainCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	drain := &bigvLogDrain{}
+	_, err := bigvClient.doJson("POST", bigvLogDrainsUrl(d, bigvClient), logDrainFromResource(d), drain)
+	if err != nil {
+		return err
+	}
+
+	if drain.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the log drain on VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(drain.Id))
+
+	log.Printf("[DEBUG] Created BigV log drain, Id: %s", d.Id())
+
+	return resourceBigvLogDrainRead(d, meta)
+}
+
+func resourceBigvLogDrainRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	drain := &bigvLogDrain{}
+	status, err := bigvClient.doJson("GET", bigvLogDrainsUrl(d, bigvClient)+"/"+d.Id(), nil, drain)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Log drain %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("destination_url", drain.DestinationUrl)
+	d.Set("filter_pattern", drain.FilterPattern)
+	d.Set("drain_type", drain.DrainType)
+	d.Set("enabled", drain.Enabled)
+
+	return readBigvLogDrainMetrics(d, bigvClient)
+}
+
+// readBigvLogDrainMetrics
+// Forwarded counts come from the metrics API rather than the drain
+func readBigvLogDrainMetrics(d *schema.ResourceData, bigvClient *client) error {
+	metrics := &bigvLogDrainMetrics{}
+	_, err := bigvClient.doJson("GET", bigvLogDrainsUrl(d, bigvClient)+"/"+d.Id()+"/metrics", nil, metrics)
+	if err != nil {
+		return err
+	}
+
+	d.Set("messages_forwarded", metrics.MessagesForwarded)
+
+	return nil
+}
+
+func resourceBigvLogDrainUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvLogDrainsUrl(d, bigvClient)+"/"+d.Id(), logDrainFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvLogDrainRead(d, meta)
+}
+
+func resourceBigvLogDrainDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvLogDrainsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestLogDrainRoundTrip(t *testing.T) {
+	drain := `{"id":8,"destination_url":"syslog+tls://logs.example.com:6514","filter_pattern":"","drain_type":"syslog","enabled":true}`
+	metrics := `{"messages_forwarded":42}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvLogDrain(), map[string]interface{}{
+		"vm_id":           "1",
+		"destination_url": "syslog+tls://logs.example.com:6514",
+	}, map[string]interface{}{
+		"enabled": false,
+	}, []testBigvCall{
+		{"POST", "/virtual_machines/1/log_drains", 0, drain},
+		{"GET", "/virtual_machines/1/log_drains/8", 0, drain},
+		{"GET", "/virtual_machines/1/log_drains/8/metrics", 0, metrics},
+		{"PUT", "/virtual_machines/1/log_drains/8", 0, drain},
+		{"GET", "/virtual_machines/1/log_drains/8", 0, drain},
+		{"GET", "/virtual_machines/1/log_drains/8/metrics", 0, metrics},
+		{"DELETE", "/virtual_machines/1/log_drains/8", http.StatusNoContent, ""},
+	})
+
+	testBigvSent(t, "create", sent[0], map[string]interface{}{
+		"destination_url": "syslog+tls://logs.example.com:6514",
+		"filter_pattern":  "",
+		"drain_type":      "syslog",
+		"enabled":         true,
+	})
+	if v, ok := sent[3]["enabled"]; !ok || v != false {
+		t.Errorf("expected the drain to be disabled, got %v", sent[3])
+	}
+
+	if d.Id() != "8" || d.Get("messages_forwarded") != 42 {
+		t.Errorf("expected drain 8 to have forwarded 42 lines, got %s with %v", d.Id(), d.Get("messages_forwarded"))
+	}
+}
+
+func TestLogDrainNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvLogDrain(), map[string]interface{}{"vm_id": "1"}, "8")
+}
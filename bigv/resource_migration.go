@@ -0,0 +1,186 @@
+package bigv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceBigvMigration
+// A one off move of a VM to another zone. Once the VM is there there's nothing left to do,
+// so later plans are empty until the VM or target_zone changes.
+func resourceBigvMigration() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvMigrationCreate,
+		Read:   resourceBigvMigrationRead,
+		Update: resourceBigvMigrationUpdate,
+		Delete: resourceBigvMigrationDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"target_zone": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+			},
+			"migrate_back_on_destroy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Move the VM back to source_zone when the migration is destroyed",
+			},
+			"source_zone": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceBigvMigrationCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vmId := d.Get("vm_id").(string)
+	target := d.Get("target_zone").(string)
+
+	vm, _, err := readBigvVMOverview(bigvClient, vmId)
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		return fmt.Errorf("VM %s not found in bigv", vmId)
+	}
+
+	d.SetId(fmt.Sprintf("%s-%s", vmId, target))
+	d.Set("source_zone", vm.Zone)
+
+	// Already there, maybe from a migration by hand
+	if vm.Zone == target {
+		log.Printf("[DEBUG] VM %s is already in %s", vmId, target)
+		return nil
+	}
+
+	if err := migrateBigvVM(bigvClient, vmId, target, d.Timeout(schema.TimeoutCreate)); err != nil {
+		d.SetId("")
+		return err
+	}
+
+	return nil
+}
+
+// resourceBigvMigrationRead
+// Only a VM that's gone takes the migration with it.
+// Moving it again elsewhere isn't undone, that'd need a new migration.
+func resourceBigvMigrationRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vm, _, err := readBigvVMOverview(bigvClient, d.Get("vm_id").(string))
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		log.Printf("[WARN] VM %s not found in bigv, removing its migration from state", d.Get("vm_id"))
+		d.SetId("")
+		return nil
+	}
+
+	return nil
+}
+
+// resourceBigvMigrationUpdate
+// Only migrate_back_on_destroy can change, and that's just in terraform
+func resourceBigvMigrationUpdate(d *schema.ResourceData, meta interface{}) error {
+	return resourceBigvMigrationRead(d, meta)
+}
+
+func resourceBigvMigrationDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	source := d.Get("source_zone").(string)
+	if !d.Get("migrate_back_on_destroy").(bool) || source == "" || source == d.Get("target_zone").(string) {
+		return nil
+	}
+
+	return migrateBigvVM(bigvClient, d.Get("vm_id").(string), source, d.Timeout(schema.TimeoutDelete))
+}
+
+// readBigvVMOverview
+// The VM by id alone, nil without an error when bigv doesn't have it.
+// The status is 202 while the VM is busy, e.g. migrating.
+func readBigvVMOverview(bigvClient *client, vmId string) (*bigvServer, int, error) {
+	url := fmt.Sprintf("%s/virtual_machines/%s?view=overview",
+		bigvClient.apiUri(),
+		vmId,
+	)
+
+	vm := &bigvServer{}
+	status, err := bigvClient.doJson("GET", url, nil, vm)
+	if status == http.StatusNotFound {
+		return nil, status, nil
+	}
+	if err != nil {
+		return nil, status, err
+	}
+
+	return vm, status, nil
+}
+
+// migrateBigvVM
+// Starts the live migration, then waits for the VM to settle in the zone
+func migrateBigvVM(bigvClient *client, vmId, zone string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(bigvClient.stopContext(), timeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/virtual_machines/%s/migrate",
+		bigvClient.apiUri(),
+		vmId,
+	)
+
+	log.Printf("[DEBUG] Migrating VM %s to %s", vmId, zone)
+
+	_, err := bigvClient.doJson("POST", url, map[string]string{
+		"zone_name": zone,
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	// Stopped on the way out, so nothing is left ticking
+	ticker := time.NewTicker(bigvClient.provisionPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return waitError(ctx, "VM migration")
+		case <-ticker.C:
+			vm, status, err := readBigvVMOverview(bigvClient, vmId)
+			if err != nil {
+				return fmt.Errorf("Error checking on VM migration: %s", err)
+			}
+			if vm == nil {
+				return fmt.Errorf("VM %s disappeared while migrating", vmId)
+			}
+
+			if status == http.StatusOK && vm.Zone == zone {
+				log.Printf("[DEBUG] VM %s is now in %s", vmId, zone)
+				return nil
+			}
+		}
+	}
+}
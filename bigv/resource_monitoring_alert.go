@@ -0,0 +1,173 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvMonitoringAlert struct {
+	Id                int     `json:"id,omitempty"`
+	Metric            string  `json:"metric,omitempty"`
+	Threshold         float64 `json:"threshold"`
+	Comparison        string  `json:"comparison,omitempty"`
+	DurationSeconds   int     `json:"duration,omitempty"`
+	NotificationEmail string  `json:"notification_email,omitempty"`
+	// Not omitempty, so that updates can disable it
+	Enabled bool `json:"enabled"`
+}
+
+var alertMetrics = []string{"cpu", "memory", "disc"}
+
+func resourceBigvMonitoringAlert() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvMonitoringAlertCreate,
+		Read:   resourceBigvMonitoringAlertRead,
+		Update: resourceBigvMonitoringAlertUpdate,
+		Delete: resourceBigvMonitoringAlertDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"metric": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice(alertMetrics, false),
+			},
+			"threshold": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Required:     true,
+				ValidateFunc: validatePercentage,
+				Description:  "Percentage utilisation of the metric, e.g. 90",
+			},
+			"comparison": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "gt",
+				ValidateFunc: validation.StringInSlice([]string{"gt", "lt"}, false),
+				Description:  "gt to alert above the threshold, lt below it",
+			},
+			"duration_seconds": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      300,
+				ValidateFunc: validation.IntAtLeast(60),
+				Description:  "How long the threshold has to be crossed for before alerting",
+			},
+			"notification_email": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func validatePercentage(v interface{}, k string) (ws []string, es []error) {
+	if p := v.(float64); p < 0 || p > 100 {
+		es = append(es, fmt.Errorf("%s must be a percentage between 0 and 100, got: %v", k, v))
+	}
+	return
+}
+
+func bigvMonitoringAlertsUrl(bigvClient *client, vmId string) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/alerts",
+		bigvClient.apiUri(),
+		vmId,
+	)
+}
+
+func monitoringAlertFromResource(d *schema.ResourceData) bigvMonitoringAlert {
+	return bigvMonitoringAlert{
+		Metric:            d.Get("metric").(string),
+		Threshold:         d.Get("threshold").(float64),
+		Comparison:        d.Get("comparison").(string),
+		DurationSeconds:   d.Get("duration_seconds").(int),
+		NotificationEmail: d.Get("notification_email").(string),
+		Enabled:           d.Get("enabled").(bool),
+	}
+}
+
+func resourceBigvMonitoringAlertCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvMonitoringAlertsUrl(bigvClient, d.Get("vm_id").(string))
+
+	alert := &bigvMonitoringAlert{}
+	if _, err := bigvClient.doJson("POST", url, monitoringAlertFromResource(d), alert); err != nil {
+		return err
+	}
+
+	if alert.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the alert on VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(alert.Id))
+
+	log.Printf("[DEBUG] Created BigV monitoring alert, Id: %s", d.Id())
+
+	return resourceBigvMonitoringAlertRead(d, meta)
+}
+
+func resourceBigvMonitoringAlertRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvMonitoringAlertsUrl(bigvClient, d.Get("vm_id").(string)) + "/" + d.Id()
+
+	alert := &bigvMonitoringAlert{}
+	status, err := bigvClient.doJson("GET", url, nil, alert)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Monitoring alert %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("metric", alert.Metric)
+	d.Set("threshold", alert.Threshold)
+	d.Set("comparison", alert.Comparison)
+	d.Set("duration_seconds", alert.DurationSeconds)
+	d.Set("notification_email", alert.NotificationEmail)
+	d.Set("enabled", alert.Enabled)
+
+	return nil
+}
+
+func resourceBigvMonitoringAlertUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvMonitoringAlertsUrl(bigvClient, d.Get("vm_id").(string)) + "/" + d.Id()
+
+	if _, err := bigvClient.doJson("PUT", url, monitoringAlertFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvMonitoringAlertRead(d, meta)
+}
+
+func resourceBigvMonitoringAlertDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	url := bigvMonitoringAlertsUrl(bigvClient, d.Get("vm_id").(string)) + "/" + d.Id()
+
+	status, err := bigvClient.doJson("DELETE", url, nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
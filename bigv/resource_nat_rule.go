@@ -0,0 +1,160 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvNatRule struct {
+	Id           int    `json:"id,omitempty"`
+	ExternalIp   string `json:"external_ip,omitempty"`
+	ExternalPort int    `json:"external_port,omitempty"`
+	InternalVmId int    `json:"internal_virtual_machine_id,omitempty"`
+	InternalPort int    `json:"internal_port,omitempty"`
+	Protocol     string `json:"protocol,omitempty"`
+	// Always sent, the update only carries this and an empty one clears it
+	Description string `json:"description"`
+}
+
+func resourceBigvNatRule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvNatRuleCreate,
+		Read:   resourceBigvNatRuleRead,
+		Update: resourceBigvNatRuleUpdate,
+		Delete: resourceBigvNatRuleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"external_ip": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPv4,
+				Description:  "The public address to forward from. bigv allocates one if not set",
+			},
+			"external_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"internal_vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"internal_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"protocol": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "tcp",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+		},
+	}
+}
+
+func bigvNatRulesUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/nat_rules",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvNatRuleCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vmId, err := strconv.Atoi(d.Get("internal_vm_id").(string))
+	if err != nil {
+		return fmt.Errorf("internal_vm_id must be a numeric VM id, got: %s", d.Get("internal_vm_id"))
+	}
+
+	rule := &bigvNatRule{}
+	_, err = bigvClient.doJson("POST", bigvNatRulesUrl(bigvClient), bigvNatRule{
+		ExternalIp:   d.Get("external_ip").(string),
+		ExternalPort: d.Get("external_port").(int),
+		InternalVmId: vmId,
+		InternalPort: d.Get("internal_port").(int),
+		Protocol:     d.Get("protocol").(string),
+		Description:  d.Get("description").(string),
+	}, rule)
+	if err != nil {
+		return err
+	}
+
+	if rule.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the NAT rule")
+	}
+
+	d.SetId(strconv.Itoa(rule.Id))
+
+	log.Printf("[DEBUG] Created BigV NAT rule %s:%d, Id: %s", rule.ExternalIp, rule.ExternalPort, d.Id())
+
+	return resourceBigvNatRuleRead(d, meta)
+}
+
+func resourceBigvNatRuleRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	rule := &bigvNatRule{}
+	status, err := bigvClient.doJson("GET", bigvNatRulesUrl(bigvClient)+"/"+d.Id(), nil, rule)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] NAT rule %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("external_ip", rule.ExternalIp)
+	d.Set("external_port", rule.ExternalPort)
+	d.Set("internal_vm_id", strconv.Itoa(rule.InternalVmId))
+	d.Set("internal_port", rule.InternalPort)
+	d.Set("protocol", rule.Protocol)
+	d.Set("description", rule.Description)
+
+	return nil
+}
+
+// resourceBigvNatRuleUpdate
+// Only the description can change, anything else is a new rule
+func resourceBigvNatRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvNatRulesUrl(bigvClient)+"/"+d.Id(), bigvNatRule{
+		Description: d.Get("description").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvNatRuleRead(d, meta)
+}
+
+func resourceBigvNatRuleDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvNatRulesUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,40 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNatRuleRoundTrip(t *testing.T) {
+	rule := `{"id":9,"external_ip":"192.0.2.80","external_port":8080,"internal_virtual_machine_id":1,"internal_port":80,"protocol":"tcp","description":"web"}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvNatRule(), map[string]interface{}{
+		"external_port":  8080,
+		"internal_vm_id": "1",
+		"internal_port":  80,
+		"description":    "web",
+	}, nil, []testBigvCall{
+		{"POST", "/accounts/myaccount/nat_rules", 0, rule},
+		{"GET", "/accounts/myaccount/nat_rules/9", 0, rule},
+		{"PUT", "/accounts/myaccount/nat_rules/9", 0, rule},
+		{"GET", "/accounts/myaccount/nat_rules/9", 0, rule},
+		{"DELETE", "/accounts/myaccount/nat_rules/9", http.StatusNoContent, ""},
+	})
+
+	testBigvSent(t, "create", sent[0], map[string]interface{}{
+		"external_port":               float64(8080),
+		"internal_virtual_machine_id": float64(1),
+		"internal_port":               float64(80),
+		"protocol":                    "tcp",
+		"description":                 "web",
+	})
+	testBigvSent(t, "update", sent[2], map[string]interface{}{"description": "web"})
+
+	if d.Id() != "9" || d.Get("external_ip") != "192.0.2.80" || d.Get("internal_vm_id") != "1" {
+		t.Errorf("expected rule 9 from 192.0.2.80 to VM 1, got %s from %v to %v", d.Id(), d.Get("external_ip"), d.Get("internal_vm_id"))
+	}
+}
+
+func TestNatRuleNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvNatRule(), map[string]interface{}{}, "9")
+}
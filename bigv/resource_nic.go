@@ -0,0 +1,138 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceBigvNic() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvNicCreate,
+		Read:   resourceBigvNicRead,
+		Delete: resourceBigvNicDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the VM to add the interface to",
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The VM's group. Defaults to the provider's default_group",
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+			"vlan_num": &schema.Schema{
+				Type:     schema.TypeInt,
+				Optional: true,
+				ForceNew: true,
+			},
+			"ipv4": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPv4,
+			},
+			"ipv6": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIPv6,
+			},
+			"mac": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvNicsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s/nics",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Get("vm_id"),
+	)
+}
+
+func resourceBigvNicCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset group is filled in here
+	if d.Get("group").(string) == "" {
+		d.Set("group", bigvClient.defaultGroup)
+	}
+
+	create := bigvNic{
+		Label:   d.Get("label").(string),
+		VlanNum: d.Get("vlan_num").(int),
+	}
+
+	// Without any bigv picks them itself
+	for _, family := range []string{"ipv4", "ipv6"} {
+		if ip := d.Get(family).(string); ip != "" {
+			create.Ips = append(create.Ips, ip)
+		}
+	}
+
+	nic := &bigvNic{}
+	if _, err := bigvClient.doJson("POST", bigvNicsUrl(d, bigvClient), create, nic); err != nil {
+		return err
+	}
+
+	if nic.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the NIC on VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(nic.Id))
+
+	log.Printf("[DEBUG] Created BigV NIC, Id: %s", d.Id())
+
+	return resourceBigvNicRead(d, meta)
+}
+
+func resourceBigvNicRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	nic := &bigvNic{}
+	status, err := bigvClient.doJson("GET", bigvNicsUrl(d, bigvClient)+"/"+d.Id(), nil, nic)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] NIC %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	ipv4, ipv6 := nic.splitIps()
+	d.Set("label", nic.Label)
+	d.Set("vlan_num", nic.VlanNum)
+	d.Set("ipv4", ipv4)
+	d.Set("ipv6", ipv6)
+	d.Set("mac", nic.Mac)
+
+	return nil
+}
+
+func resourceBigvNicDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvNicsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,209 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvBucket struct {
+	Id     int    `json:"id,omitempty"`
+	Name   string `json:"name,omitempty"`
+	Region string `json:"region,omitempty"`
+	Acl    string `json:"acl,omitempty"`
+	// Sent even when false or empty, so an update can turn versioning off or drop every rule
+	Versioning     bool                  `json:"versioning_enabled"`
+	LifecycleRules []bigvBucketLifecycle `json:"lifecycle_rules"`
+
+	// Read Attributes
+	EndpointUrl string `json:"endpoint_url,omitempty"`
+	AccessKey   string `json:"access_key,omitempty"`
+	// Only sent back when the bucket is made
+	SecretKey string `json:"secret_key,omitempty"`
+}
+
+type bigvBucketLifecycle struct {
+	Prefix         string `json:"prefix"`
+	ExpirationDays int    `json:"expiration_days"`
+}
+
+func resourceBigvObjectStorage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvObjectStorageCreate,
+		Read:   resourceBigvObjectStorageRead,
+		Update: resourceBigvObjectStorageUpdate,
+		Delete: resourceBigvObjectStorageDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The bucket name, which has to be unique across bigv",
+			},
+			"region": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "york",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+			},
+			"acl": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "private",
+				ValidateFunc: validation.StringInSlice([]string{"public", "private"}, false),
+				Description:  "public buckets can be read by anyone, private only with the keys",
+			},
+			"versioning_enabled": &schema.Schema{
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  false,
+			},
+			"lifecycle_rules": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Expire objects under a prefix after a number of days",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"prefix": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"expiration_days": &schema.Schema{
+							Type:         schema.TypeInt,
+							Required:     true,
+							ValidateFunc: validation.IntAtLeast(1),
+						},
+					},
+				},
+			},
+			"endpoint_url": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"access_key": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+			"secret_key": &schema.Schema{
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
+			},
+		},
+	}
+}
+
+func bigvBucketsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/object_storage",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func bucketFromResource(d *schema.ResourceData) bigvBucket {
+	bucket := bigvBucket{
+		Name:           d.Get("name").(string),
+		Region:         d.Get("region").(string),
+		Acl:            d.Get("acl").(string),
+		Versioning:     d.Get("versioning_enabled").(bool),
+		LifecycleRules: []bigvBucketLifecycle{},
+	}
+
+	for _, v := range d.Get("lifecycle_rules").([]interface{}) {
+		rule := v.(map[string]interface{})
+		bucket.LifecycleRules = append(bucket.LifecycleRules, bigvBucketLifecycle{
+			Prefix:         rule["prefix"].(string),
+			ExpirationDays: rule["expiration_days"].(int),
+		})
+	}
+
+	return bucket
+}
+
+func resourceBigvObjectStorageCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	bucket := &bigvBucket{}
+	if _, err := bigvClient.doJson("POST", bigvBucketsUrl(bigvClient), bucketFromResource(d), bucket); err != nil {
+		return err
+	}
+
+	if bucket.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for bucket %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(bucket.Id))
+
+	// There's no getting it back later
+	d.Set("secret_key", bucket.SecretKey)
+
+	log.Printf("[DEBUG] Created BigV object storage bucket, Id: %s", d.Id())
+
+	return resourceBigvObjectStorageRead(d, meta)
+}
+
+// resourceBigvObjectStorageRead
+// Never sets secret_key, bigv only gives it out once
+func resourceBigvObjectStorageRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	bucket := &bigvBucket{}
+	status, err := bigvClient.doJson("GET", bigvBucketsUrl(bigvClient)+"/"+d.Id(), nil, bucket)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Object storage bucket %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rules := make([]interface{}, len(bucket.LifecycleRules))
+	for i, rule := range bucket.LifecycleRules {
+		rules[i] = map[string]interface{}{
+			"prefix":          rule.Prefix,
+			"expiration_days": rule.ExpirationDays,
+		}
+	}
+
+	d.Set("name", bucket.Name)
+	d.Set("region", bucket.Region)
+	d.Set("acl", bucket.Acl)
+	d.Set("versioning_enabled", bucket.Versioning)
+	d.Set("lifecycle_rules", rules)
+	d.Set("endpoint_url", bucket.EndpointUrl)
+	d.Set("access_key", bucket.AccessKey)
+
+	return nil
+}
+
+func resourceBigvObjectStorageUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if _, err := bigvClient.doJson("PUT", bigvBucketsUrl(bigvClient)+"/"+d.Id(), bucketFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvObjectStorageRead(d, meta)
+}
+
+// resourceBigvObjectStorageDelete
+// Takes every object in the bucket with it
+func resourceBigvObjectStorageDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvBucketsUrl(bigvClient)+"/"+d.Id()+"?purge=true", nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,59 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestObjectStorageRoundTrip(t *testing.T) {
+	bucket := `{"id":5,"name":"backups","region":"york","acl":"private","versioning_enabled":true,` +
+		`"lifecycle_rules":[{"prefix":"logs/","expiration_days":30}],` +
+		`"endpoint_url":"https://backups.storage.example","access_key":"AKEY"}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvObjectStorage(), map[string]interface{}{
+		"name":               "backups",
+		"versioning_enabled": true,
+		"lifecycle_rules": []interface{}{
+			map[string]interface{}{"prefix": "logs/", "expiration_days": 30},
+		},
+	}, map[string]interface{}{
+		"versioning_enabled": false,
+		"lifecycle_rules":    []interface{}{},
+	}, []testBigvCall{
+		{"POST", "/accounts/myaccount/object_storage", 0, `{"id":5,"secret_key":"s3cret"}`},
+		{"GET", "/accounts/myaccount/object_storage/5", 0, bucket},
+		{"PUT", "/accounts/myaccount/object_storage/5", 0, bucket},
+		{"GET", "/accounts/myaccount/object_storage/5", 0, bucket},
+		{"DELETE", "/accounts/myaccount/object_storage/5?purge=true", http.StatusNoContent, ""},
+	})
+
+	// The secret only comes back from the create, so the reads mustn't lose it
+	if d.Get("secret_key") != "s3cret" || d.Get("access_key") != "AKEY" {
+		t.Errorf("expected both keys to be kept, got %v and %v", d.Get("access_key"), d.Get("secret_key"))
+	}
+
+	create := sent[0]
+	if create["name"] != "backups" || create["region"] != "york" || create["acl"] != "private" || create["versioning_enabled"] != true {
+		t.Errorf("expected a private, versioned bucket in york, got %v", create)
+	}
+	rules, _ := create["lifecycle_rules"].([]interface{})
+	if len(rules) != 1 {
+		t.Fatalf("expected one lifecycle rule, got %v", create["lifecycle_rules"])
+	}
+	if rule := rules[0].(map[string]interface{}); rule["prefix"] != "logs/" || rule["expiration_days"] != float64(30) {
+		t.Errorf("expected logs/ to expire after 30 days, got %v", rule)
+	}
+
+	// Turning things off has to be sent, not left out
+	update := sent[2]
+	if v, ok := update["versioning_enabled"]; !ok || v != false {
+		t.Errorf("expected versioning to be turned off, got %v", update)
+	}
+	if rules, ok := update["lifecycle_rules"].([]interface{}); !ok || len(rules) != 0 {
+		t.Errorf("expected an empty list of lifecycle rules, got %v", update["lifecycle_rules"])
+	}
+}
+
+func TestObjectStorageNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvObjectStorage(), map[string]interface{}{"name": "backups"}, "5")
+}
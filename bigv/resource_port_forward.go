@@ -0,0 +1,152 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvPortForward struct {
+	Id           int    `json:"id,omitempty"`
+	ExternalPort int    `json:"external_port,omitempty"`
+	InternalPort int    `json:"internal_port,omitempty"`
+	Protocol     string `json:"protocol,omitempty"`
+	// Left in when empty, which is how a description gets removed
+	Description string `json:"description"`
+
+	// Read Attributes
+	ExternalIp string `json:"external_ip,omitempty"`
+}
+
+// resourceBigvPortForward
+// Forwards a port on the VM's own address to another of its ports,
+// unlike bigv_nat_rule which forwards from a separate public address
+func resourceBigvPortForward() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvPortForwardCreate,
+		Read:   resourceBigvPortForwardRead,
+		Update: resourceBigvPortForwardUpdate,
+		Delete: resourceBigvPortForwardDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"external_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"internal_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 65535),
+			},
+			"protocol": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "tcp",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"external_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvPortForwardsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/port_forwards",
+		bigvClient.apiUri(),
+		d.Get("vm_id"),
+	)
+}
+
+func resourceBigvPortForwardCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	forward := &bigvPortForward{}
+	_, err := bigvClient.doJson("POST", bigvPortForwardsUrl(d, bigvClient), bigvPortForward{
+		ExternalPort: d.Get("external_port").(int),
+		InternalPort: d.Get("internal_port").(int),
+		Protocol:     d.Get("protocol").(string),
+		Description:  d.Get("description").(string),
+	}, forward)
+	if err != nil {
+		return err
+	}
+
+	if forward.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the port forward on VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(forward.Id))
+
+	log.Printf("[DEBUG] Created BigV port forward %d->%d, Id: %s", forward.ExternalPort, forward.InternalPort, d.Id())
+
+	return resourceBigvPortForwardRead(d, meta)
+}
+
+func resourceBigvPortForwardRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	forward := &bigvPortForward{}
+	status, err := bigvClient.doJson("GET", bigvPortForwardsUrl(d, bigvClient)+"/"+d.Id(), nil, forward)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Port forward %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("external_port", forward.ExternalPort)
+	d.Set("internal_port", forward.InternalPort)
+	d.Set("protocol", forward.Protocol)
+	d.Set("description", forward.Description)
+	d.Set("external_ip", forward.ExternalIp)
+
+	return nil
+}
+
+// resourceBigvPortForwardUpdate
+// Changing the ports or protocol replaces the forward, which leaves the description
+func resourceBigvPortForwardUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvPortForwardsUrl(d, bigvClient)+"/"+d.Id(), bigvPortForward{
+		Description: d.Get("description").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvPortForwardRead(d, meta)
+}
+
+func resourceBigvPortForwardDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvPortForwardsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
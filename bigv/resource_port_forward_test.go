@@ -0,0 +1,56 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestPortForwardRoundTrip(t *testing.T) {
+	forward := `{"id":2,"external_port":2222,"internal_port":22,"protocol":"tcp","description":"","external_ip":"192.0.2.10"}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvPortForward(), map[string]interface{}{
+		"vm_id":         "1",
+		"external_port": 2222,
+		"internal_port": 22,
+	}, nil, []testBigvCall{
+		{"POST", "/virtual_machines/1/port_forwards", 0, forward},
+		{"GET", "/virtual_machines/1/port_forwards/2", 0, forward},
+		{"PUT", "/virtual_machines/1/port_forwards/2", 0, forward},
+		{"GET", "/virtual_machines/1/port_forwards/2", 0, forward},
+		{"DELETE", "/virtual_machines/1/port_forwards/2", http.StatusNoContent, ""},
+	})
+
+	testBigvSent(t, "create", sent[0], map[string]interface{}{
+		"external_port": float64(2222),
+		"internal_port": float64(22),
+		"protocol":      "tcp",
+		"description":   "",
+	})
+	// An empty description still goes, so it can be cleared
+	testBigvSent(t, "update", sent[2], map[string]interface{}{"description": ""})
+
+	if d.Id() != "2" || d.Get("external_ip") != "192.0.2.10" {
+		t.Errorf("expected forward 2 on 192.0.2.10, got %s on %v", d.Id(), d.Get("external_ip"))
+	}
+}
+
+func TestPortForwardPortRange(t *testing.T) {
+	s := resourceBigvPortForward().Schema
+
+	for _, key := range []string{"external_port", "internal_port"} {
+		for _, port := range []int{1, 22, 65535} {
+			if _, es := s[key].ValidateFunc(port, key); len(es) > 0 {
+				t.Errorf("%s %d: expected it to be valid, got %s", key, port, es)
+			}
+		}
+		for _, port := range []int{0, -1, 65536} {
+			if _, es := s[key].ValidateFunc(port, key); len(es) == 0 {
+				t.Errorf("%s %d: expected it to be out of range", key, port)
+			}
+		}
+	}
+}
+
+func TestPortForwardNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvPortForward(), map[string]interface{}{"vm_id": "1"}, "2")
+}
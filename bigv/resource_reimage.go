@@ -0,0 +1,152 @@
+package bigv
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// resourceBigvReimage
+// Puts a fresh os on an existing VM, keeping its id, ips and everything attached to it.
+// Changing anything reimages it again.
+func resourceBigvReimage() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvReimageCreate,
+		Read:   resourceBigvReimageRead,
+		Delete: resourceBigvReimageDelete,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(waitForVM * time.Second),
+		},
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"distribution": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(knownDistributions(), true),
+				StateFunc:    lowercase,
+			},
+			"root_password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Sensitive:   true,
+				Description: "Generated if not given",
+			},
+			"ssh_public_key": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				ForceNew: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"firstboot_script": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+			},
+		},
+	}
+}
+
+func resourceBigvReimageCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ctx, cancel := context.WithTimeout(bigvClient.stopContext(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	vmId := d.Get("vm_id").(string)
+
+	rootPassword := d.Get("root_password").(string)
+	if rootPassword == "" {
+		var err error
+		if rootPassword, err = randomPassword(); err != nil {
+			return err
+		}
+	}
+
+	var keys []string
+	for _, key := range d.Get("ssh_public_key").([]interface{}) {
+		keys = append(keys, strings.TrimSpace(key.(string)))
+	}
+
+	url := fmt.Sprintf("%s/virtual_machines/%s/reimage",
+		bigvClient.apiUri(),
+		vmId,
+	)
+
+	log.Printf("[DEBUG] Reimaging VM %s with %s", vmId, d.Get("distribution"))
+
+	_, err := bigvClient.doJson("POST", url, bigvImage{
+		Distribution:    d.Get("distribution").(string),
+		RootPassword:    rootPassword,
+		SshPublicKey:    strings.Join(keys, "\n"),
+		FirstBootScript: d.Get("firstboot_script").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId(vmId)
+	d.Set("root_password", rootPassword)
+
+	// Stopped on the way out, so nothing is left ticking
+	ticker := time.NewTicker(bigvClient.provisionPollInterval)
+	defer ticker.Stop()
+
+	// bigv says Accepted until it's finished imaging
+	for {
+		select {
+		case <-ctx.Done():
+			return waitError(ctx, "VM reimage")
+		case <-ticker.C:
+			vm, status, err := readBigvVMOverview(bigvClient, vmId)
+			if err != nil {
+				return fmt.Errorf("Error checking on VM reimage: %s", err)
+			}
+			if vm == nil {
+				return fmt.Errorf("VM %s disappeared while reimaging", vmId)
+			}
+
+			if status == http.StatusOK {
+				log.Printf("[DEBUG] VM %s is reimaged", vmId)
+				return nil
+			}
+		}
+	}
+}
+
+// resourceBigvReimageRead
+// Only a VM that's gone takes the reimage with it
+func resourceBigvReimageRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vm, _, err := readBigvVMOverview(bigvClient, d.Id())
+	if err != nil {
+		return err
+	}
+	if vm == nil {
+		log.Printf("[WARN] VM %s not found in bigv, removing its reimage from state", d.Id())
+		d.SetId("")
+	}
+
+	return nil
+}
+
+// resourceBigvReimageDelete
+// There's no un-imaging a VM, so this just forgets it
+func resourceBigvReimageDelete(d *schema.ResourceData, meta interface{}) error {
+	return nil
+}
@@ -0,0 +1,139 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceBigvReverseDns() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvReverseDnsCreate,
+		Read:   resourceBigvReverseDnsRead,
+		Update: resourceBigvReverseDnsUpdate,
+		Delete: resourceBigvReverseDnsDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceBigvReverseDnsImport,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"ip_address": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateIP,
+				Description:  "An ipv4 or ipv6 address bigv has given the account",
+			},
+			"hostname": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name the PTR record points to",
+			},
+			"ip_version": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// ipVersion
+// 4 or 6, or 0 for something that isn't an ip
+func ipVersion(address string) int {
+	ip := net.ParseIP(address)
+	switch {
+	case ip == nil:
+		return 0
+	case ip.To4() != nil:
+		return 4
+	default:
+		return 6
+	}
+}
+
+func bigvReverseDnsUrl(bigvClient *client, address string) string {
+	return fmt.Sprintf("%s/ips/%s", bigvClient.apiUri(), address)
+}
+
+func resourceBigvReverseDnsCreate(d *schema.ResourceData, meta interface{}) error {
+	address := d.Get("ip_address").(string)
+
+	// Ids are the address as bigv knows it, as ipv6 can be written many ways
+	d.SetId(net.ParseIP(address).String())
+
+	if err := resourceBigvReverseDnsUpdate(d, meta); err != nil {
+		d.SetId("")
+		return err
+	}
+
+	return nil
+}
+
+func resourceBigvReverseDnsRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	ip := &bigvIp{}
+	status, err := bigvClient.doJson("GET", bigvReverseDnsUrl(bigvClient, d.Id()), nil, ip)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] IP %s no longer belongs to the account, removing its reverse DNS from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("hostname", ip.Rdns)
+	d.Set("ip_version", ipVersion(d.Id()))
+
+	return nil
+}
+
+func resourceBigvReverseDnsUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvReverseDnsUrl(bigvClient, d.Id()), bigvIp{
+		Rdns: d.Get("hostname").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Set BigV reverse DNS for %s to %s", d.Id(), d.Get("hostname"))
+
+	return resourceBigvReverseDnsRead(d, meta)
+}
+
+// resourceBigvReverseDnsDelete
+// Blanking the PTR record gives the ip back bigv's default.
+// The ip going first, along with its VM or reservation, leaves nothing to do.
+func resourceBigvReverseDnsDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("PUT", bigvReverseDnsUrl(bigvClient, d.Id()), map[string]string{
+		"rdns": "",
+	}, nil)
+	if status == http.StatusNotFound {
+		log.Printf("[DEBUG] IP %s already gone, so is its reverse DNS", d.Id())
+		return nil
+	}
+
+	return err
+}
+
+// resourceBigvReverseDnsImport
+// Imported by ip address
+func resourceBigvReverseDnsImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ip := net.ParseIP(d.Id())
+	if ip == nil {
+		return nil, fmt.Errorf("Reverse DNS is imported by ip address, got: %s", d.Id())
+	}
+
+	d.SetId(ip.String())
+	d.Set("ip_address", ip.String())
+
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,167 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvSchedule struct {
+	Id             int    `json:"id,omitempty"`
+	Action         string `json:"action,omitempty"`
+	CronExpression string `json:"cron_expression,omitempty"`
+	Timezone       string `json:"timezone,omitempty"`
+	Enabled        bool   `json:"enabled"`
+
+	// Read Attributes
+	NextExecution string `json:"next_execution,omitempty"`
+}
+
+func resourceBigvSchedule() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvScheduleCreate,
+		Read:   resourceBigvScheduleRead,
+		Update: resourceBigvScheduleUpdate,
+		Delete: resourceBigvScheduleDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"action": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"power_on", "power_off"}, false),
+			},
+			"cron_expression": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateCronExpression,
+				Description:  "When to run, as minute hour day-of-month month day-of-week",
+			},
+			"timezone": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "Europe/London",
+				ValidateFunc: validateTimezone,
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Disabled schedules are kept, but never run",
+			},
+			"next_execution": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "When the schedule next runs, empty while disabled",
+			},
+		},
+	}
+}
+
+// validateCronExpression
+// Just the five standard fields, bigv checks what's in them
+func validateCronExpression(v interface{}, k string) (ws []string, es []error) {
+	if fields := strings.Fields(v.(string)); len(fields) != 5 {
+		es = append(es, fmt.Errorf("%s must have 5 fields, minute hour day-of-month month day-of-week, got: %s", k, v))
+	}
+	return
+}
+
+func validateTimezone(v interface{}, k string) (ws []string, es []error) {
+	if _, err := time.LoadLocation(v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be a timezone such as Europe/London, got: %s", k, v))
+	}
+	return
+}
+
+func bigvSchedulesUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/schedules",
+		bigvClient.apiUri(),
+		d.Get("vm_id"),
+	)
+}
+
+func scheduleFromResource(d *schema.ResourceData) bigvSchedule {
+	return bigvSchedule{
+		Action:         d.Get("action").(string),
+		CronExpression: d.Get("cron_expression").(string),
+		Timezone:       d.Get("timezone").(string),
+		Enabled:        d.Get("enabled").(bool),
+	}
+}
+
+func resourceBigvScheduleCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	schedule := &bigvSchedule{}
+	_, err := bigvClient.doJson("POST", bigvSchedulesUrl(d, bigvClient), scheduleFromResource(d), schedule)
+	if err != nil {
+		return err
+	}
+
+	if schedule.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the %s schedule on VM %s", d.Get("action"), d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(schedule.Id))
+
+	log.Printf("[DEBUG] Created BigV schedule, Id: %s", d.Id())
+
+	return resourceBigvScheduleRead(d, meta)
+}
+
+func resourceBigvScheduleRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	schedule := &bigvSchedule{}
+	status, err := bigvClient.doJson("GET", bigvSchedulesUrl(d, bigvClient)+"/"+d.Id(), nil, schedule)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Schedule %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("action", schedule.Action)
+	d.Set("cron_expression", schedule.CronExpression)
+	d.Set("timezone", schedule.Timezone)
+	d.Set("enabled", schedule.Enabled)
+	d.Set("next_execution", schedule.NextExecution)
+
+	return nil
+}
+
+func resourceBigvScheduleUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvSchedulesUrl(d, bigvClient)+"/"+d.Id(), scheduleFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvScheduleRead(d, meta)
+}
+
+func resourceBigvScheduleDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvSchedulesUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
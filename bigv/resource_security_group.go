@@ -0,0 +1,240 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvSecurityGroup struct {
+	Id   int    `json:"id,omitempty"`
+	Name string `json:"name,omitempty"`
+	// Not omitempty, so that updates can remove every rule
+	Rules []bigvSecurityGroupRule `json:"rules"`
+
+	// Read Attributes
+	VmIds []int `json:"virtual_machine_ids,omitempty"`
+}
+
+type bigvSecurityGroupRule struct {
+	Direction string `json:"direction,omitempty"`
+	Protocol  string `json:"protocol,omitempty"`
+	PortRange string `json:"port_range,omitempty"`
+	Cidr      string `json:"cidr,omitempty"`
+	Action    string `json:"action,omitempty"`
+	Priority  int    `json:"priority,omitempty"`
+}
+
+// securityGroupRuleSchema
+// Firewall rules, but with one cidr for whichever end isn't the VM
+func securityGroupRuleSchema() map[string]*schema.Schema {
+	s := firewallRuleSchema()
+	delete(s, "source_cidr")
+	delete(s, "destination_cidr")
+
+	s["cidr"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Default:      "0.0.0.0/0",
+		ValidateFunc: validation.CIDRNetwork(0, 128),
+		Description:  "Where inbound traffic comes from, or outbound traffic goes to",
+	}
+
+	return s
+}
+
+func resourceBigvSecurityGroup() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvSecurityGroupCreate,
+		Read:   resourceBigvSecurityGroupRead,
+		Update: resourceBigvSecurityGroupUpdate,
+		Delete: resourceBigvSecurityGroupDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"rule": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: securityGroupRuleSchema(),
+				},
+			},
+			"vm_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "The VMs the group is attached to, by their security_group_ids",
+			},
+		},
+	}
+}
+
+func bigvSecurityGroupsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/security_groups",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func securityGroupFromResource(d *schema.ResourceData) bigvSecurityGroup {
+	group := bigvSecurityGroup{
+		Name:  d.Get("name").(string),
+		Rules: []bigvSecurityGroupRule{},
+	}
+
+	for _, r := range d.Get("rule").([]interface{}) {
+		rule := r.(map[string]interface{})
+		group.Rules = append(group.Rules, bigvSecurityGroupRule{
+			Direction: rule["direction"].(string),
+			Protocol:  rule["protocol"].(string),
+			PortRange: rule["port_range"].(string),
+			Cidr:      rule["cidr"].(string),
+			Action:    rule["action"].(string),
+			Priority:  rule["priority"].(int),
+		})
+	}
+
+	return group
+}
+
+func resourceBigvSecurityGroupCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group := &bigvSecurityGroup{}
+	_, err := bigvClient.doJson("POST", bigvSecurityGroupsUrl(bigvClient), securityGroupFromResource(d), group)
+	if err != nil {
+		return err
+	}
+
+	if group.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for security group %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(group.Id))
+
+	log.Printf("[DEBUG] Created BigV security group, Id: %s", d.Id())
+
+	return resourceBigvSecurityGroupRead(d, meta)
+}
+
+func resourceBigvSecurityGroupRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	group := &bigvSecurityGroup{}
+	status, err := bigvClient.doJson("GET", bigvSecurityGroupsUrl(bigvClient)+"/"+d.Id(), nil, group)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Security group %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	rules := make([]map[string]interface{}, len(group.Rules))
+	for i, rule := range group.Rules {
+		rules[i] = map[string]interface{}{
+			"direction":  rule.Direction,
+			"protocol":   rule.Protocol,
+			"port_range": rule.PortRange,
+			"cidr":       rule.Cidr,
+			"action":     rule.Action,
+			"priority":   rule.Priority,
+		}
+	}
+
+	vmIds := make([]interface{}, len(group.VmIds))
+	for i, id := range group.VmIds {
+		vmIds[i] = strconv.Itoa(id)
+	}
+
+	d.Set("name", group.Name)
+	d.Set("rule", rules)
+	d.Set("vm_ids", schema.NewSet(schema.HashString, vmIds))
+
+	return nil
+}
+
+// resourceBigvSecurityGroupUpdate
+// Rules are replaced as a whole, and bigv rebuilds the firewall of every attached VM from them
+func resourceBigvSecurityGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvSecurityGroupsUrl(bigvClient)+"/"+d.Id(), securityGroupFromResource(d), nil)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[DEBUG] Updated BigV security group %s, attached to VMs: %v", d.Id(), d.Get("vm_ids").(*schema.Set).List())
+
+	return resourceBigvSecurityGroupRead(d, meta)
+}
+
+func resourceBigvSecurityGroupDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvSecurityGroupsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
+
+// bigvVMSecurityGroupsUrl
+// The security groups attached to a VM, replaced as a whole
+func bigvVMSecurityGroupsUrl(bigvClient *client, vmId string) string {
+	return fmt.Sprintf("%s/virtual_machines/%s/security_groups",
+		bigvClient.apiUri(),
+		vmId,
+	)
+}
+
+type bigvVMSecurityGroups struct {
+	// Not omitempty, so that every group can be detached
+	Ids []int `json:"security_group_ids"`
+}
+
+// setBigvVMSecurityGroups
+// Attaches exactly the VM's security_group_ids, detaching any others
+func setBigvVMSecurityGroups(d *schema.ResourceData, bigvClient *client) error {
+	groups := bigvVMSecurityGroups{Ids: []int{}}
+	for _, id := range d.Get("security_group_ids").(*schema.Set).List() {
+		i, err := strconv.Atoi(id.(string))
+		if err != nil {
+			return fmt.Errorf("security_group_ids must be numeric security group ids, got: %s", id)
+		}
+		groups.Ids = append(groups.Ids, i)
+	}
+
+	_, err := bigvClient.doJson("PUT", bigvVMSecurityGroupsUrl(bigvClient, d.Id()), groups, nil)
+	return err
+}
+
+func readBigvVMSecurityGroups(d *schema.ResourceData, bigvClient *client) error {
+	groups := &bigvVMSecurityGroups{}
+	if _, err := bigvClient.doJson("GET", bigvVMSecurityGroupsUrl(bigvClient, d.Id()), nil, groups); err != nil {
+		return err
+	}
+
+	ids := make([]interface{}, len(groups.Ids))
+	for i, id := range groups.Ids {
+		ids[i] = strconv.Itoa(id)
+	}
+
+	d.Set("security_group_ids", schema.NewSet(schema.HashString, ids))
+
+	return nil
+}
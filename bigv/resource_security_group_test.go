@@ -0,0 +1,67 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestSecurityGroupUpdateSendsRules(t *testing.T) {
+	// Both VMs share the group, so both get its rules
+	group := `{"id":3,"name":"web","rules":[{"direction":"in","protocol":"tcp","port_range":"443","cidr":"0.0.0.0/0","action":"accept","priority":10}],"virtual_machine_ids":[1,2]}`
+
+	c, server, sent, check := testBigvCalls(t, []testBigvCall{
+		{"PUT", "/accounts/myaccount/security_groups/3", 0, group},
+		{"GET", "/accounts/myaccount/security_groups/3", 0, group},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceBigvSecurityGroup().Schema, map[string]interface{}{
+		"name": "web",
+		"rule": []interface{}{
+			map[string]interface{}{"direction": "in", "protocol": "tcp", "port_range": "443", "action": "accept", "priority": 10},
+		},
+	})
+	d.SetId("3")
+
+	if err := resourceBigvSecurityGroupUpdate(d, c); err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	rules, _ := sent[0]["rules"].([]interface{})
+	if len(rules) != 1 {
+		t.Fatalf("expected the rule to be sent, got %v", sent[0]["rules"])
+	}
+	if rule := rules[0].(map[string]interface{}); rule["port_range"] != "443" || rule["cidr"] != "0.0.0.0/0" {
+		t.Fatalf("expected 443 to be opened to everyone, got %v", rule)
+	}
+	if vms := d.Get("vm_ids").(*schema.Set); vms.Len() != 2 || !vms.Contains("1") || !vms.Contains("2") {
+		t.Fatalf("expected both VMs attached, got %v", vms.List())
+	}
+}
+
+func TestSecurityGroupNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvSecurityGroup(), map[string]interface{}{"name": "web"}, "3")
+}
+
+func TestVMSecurityGroupsNotComputed(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		// Attached outside terraform
+		if r.URL.Path == "/virtual_machines/1/security_groups" {
+			w.Write([]byte(`{"security_group_ids":[3]}`))
+			return
+		}
+		fake.ServeHTTP(w, r)
+	})
+	defer server.Close()
+
+	state := testVMRefresh(t, c, nil)
+
+	diff := testVMPlan(t, c, state, map[string]interface{}{"name": "web"})
+	if diff == nil || diff.Attributes["security_group_ids.#"] == nil || diff.Attributes["security_group_ids.#"].New != "0" {
+		t.Fatalf("expected the plan to detach the security group, got %#v", diff)
+	}
+}
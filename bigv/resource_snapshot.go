@@ -0,0 +1,145 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvSnapshot struct {
+	Id     int    `json:"id,omitempty"`
+	Label  string `json:"label,omitempty"`
+	Expiry string `json:"expires_at,omitempty"`
+
+	// Read Attributes
+	Size   int    `json:"size,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+func resourceBigvSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvSnapshotCreate,
+		Read:   resourceBigvSnapshotRead,
+		Delete: resourceBigvSnapshotDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vm_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The id of the VM to snapshot",
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The VM's group. Defaults to the provider's default_group",
+			},
+			"label": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ForceNew: true,
+			},
+			"expiry": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ValidateFunc: validateRFC3339,
+				Description:  "When bigv should delete the snapshot, e.g. 2017-01-31T00:00:00Z. Never by default",
+			},
+			"size": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func validateRFC3339(v interface{}, k string) (ws []string, es []error) {
+	if _, err := time.Parse(time.RFC3339, v.(string)); err != nil {
+		es = append(es, fmt.Errorf("%s must be an RFC3339 time such as 2017-01-31T00:00:00Z, got: %s", k, v))
+	}
+	return
+}
+
+func bigvSnapshotsUrl(d *schema.ResourceData, bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s/backups",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Get("vm_id"),
+	)
+}
+
+func resourceBigvSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	// Schema defaults can't see the provider's config, so an unset group is filled in here
+	if d.Get("group").(string) == "" {
+		d.Set("group", bigvClient.defaultGroup)
+	}
+
+	snapshot := &bigvSnapshot{}
+	_, err := bigvClient.doJson("POST", bigvSnapshotsUrl(d, bigvClient), bigvSnapshot{
+		Label:  d.Get("label").(string),
+		Expiry: d.Get("expiry").(string),
+	}, snapshot)
+	if err != nil {
+		return err
+	}
+
+	if snapshot.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for the snapshot of VM %s", d.Get("vm_id"))
+	}
+
+	d.SetId(strconv.Itoa(snapshot.Id))
+
+	log.Printf("[DEBUG] Created BigV snapshot, Id: %s", d.Id())
+
+	return resourceBigvSnapshotRead(d, meta)
+}
+
+// resourceBigvSnapshotRead
+// Snapshots are taken in the background, status says when they're complete
+func resourceBigvSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	snapshot := &bigvSnapshot{}
+	status, err := bigvClient.doJson("GET", bigvSnapshotsUrl(d, bigvClient)+"/"+d.Id(), nil, snapshot)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] Snapshot %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	snapshotToResource(d, snapshot)
+
+	return nil
+}
+
+func snapshotToResource(d *schema.ResourceData, snapshot *bigvSnapshot) {
+	d.Set("label", snapshot.Label)
+	d.Set("expiry", snapshot.Expiry)
+	d.Set("size", snapshot.Size)
+	d.Set("status", snapshot.Status)
+}
+
+func resourceBigvSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvSnapshotsUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
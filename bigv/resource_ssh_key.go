@@ -0,0 +1,151 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"golang.org/x/crypto/ssh"
+)
+
+type bigvSshKey struct {
+	Id        int    `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	PublicKey string `json:"public_key,omitempty"`
+}
+
+func resourceBigvSshKey() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvSshKeyCreate,
+		Read:   resourceBigvSshKeyRead,
+		Delete: resourceBigvSshKeyDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name VMs refer to the key by in ssh_key_names",
+			},
+			"public_key": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validateSshPublicKey,
+				StateFunc:    trimSpace,
+			},
+			"fingerprint": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// trimSpace
+// Keys read with file() come with a trailing newline
+func trimSpace(v interface{}) string {
+	return strings.TrimSpace(v.(string))
+}
+
+func validateSshPublicKey(v interface{}, k string) (ws []string, es []error) {
+	if _, _, _, _, err := ssh.ParseAuthorizedKey([]byte(v.(string))); err != nil {
+		es = append(es, fmt.Errorf("%s must be an ssh public key in authorized_keys format: %s", k, err))
+	}
+	return
+}
+
+func bigvSshKeysUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/ssh_keys",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvSshKeyCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	key := &bigvSshKey{}
+	_, err := bigvClient.doJson("POST", bigvSshKeysUrl(bigvClient), bigvSshKey{
+		Name:      d.Get("name").(string),
+		PublicKey: strings.TrimSpace(d.Get("public_key").(string)),
+	}, key)
+	if err != nil {
+		return err
+	}
+
+	if key.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for ssh key %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(key.Id))
+
+	log.Printf("[DEBUG] Created BigV ssh key, Id: %s", d.Id())
+
+	return resourceBigvSshKeyRead(d, meta)
+}
+
+func resourceBigvSshKeyRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	key := &bigvSshKey{}
+	status, err := bigvClient.doJson("GET", bigvSshKeysUrl(bigvClient)+"/"+d.Id(), nil, key)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] ssh key %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", key.Name)
+	d.Set("public_key", key.PublicKey)
+
+	// bigv doesn't give us one, so work it out the same way ssh-keygen -l does
+	publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key.PublicKey))
+	if err != nil {
+		return fmt.Errorf("Couldn't parse ssh key %s from bigv: %s", d.Id(), err)
+	}
+	d.Set("fingerprint", ssh.FingerprintSHA256(publicKey))
+
+	return nil
+}
+
+func resourceBigvSshKeyDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvSshKeysUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
+
+// readBigvSshKeysByName
+// The public keys for the given names, in the same order
+func readBigvSshKeysByName(bigvClient *client, names []string) ([]string, error) {
+	var keys []bigvSshKey
+	if _, err := bigvClient.doJson("GET", bigvSshKeysUrl(bigvClient), nil, &keys); err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]string, len(keys))
+	for _, key := range keys {
+		byName[key.Name] = key.PublicKey
+	}
+
+	found := make([]string, 0, len(names))
+	for _, name := range names {
+		key, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("No ssh key named %s in bigv account %s", name, bigvClient.account)
+		}
+		found = append(found, key)
+	}
+
+	return found, nil
+}
@@ -0,0 +1,138 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+type bigvUser struct {
+	Id       int    `json:"id,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	// Not omitempty, so that updates can clear them
+	Admin  bool     `json:"admin"`
+	Groups []string `json:"groups"`
+}
+
+func resourceBigvUser() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvUserCreate,
+		Read:   resourceBigvUserRead,
+		Update: resourceBigvUserUpdate,
+		Delete: resourceBigvUserDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Schema: map[string]*schema.Schema{
+			"username": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"email": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"admin": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Admins can manage everything in the account, including its users",
+			},
+			"groups": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the groups the user can manage",
+			},
+		},
+	}
+}
+
+func bigvUsersUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/users",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func userFromResource(d *schema.ResourceData) bigvUser {
+	groups := []string{}
+	for _, group := range d.Get("groups").([]interface{}) {
+		groups = append(groups, group.(string))
+	}
+
+	return bigvUser{
+		Username: d.Get("username").(string),
+		Email:    d.Get("email").(string),
+		Admin:    d.Get("admin").(bool),
+		Groups:   groups,
+	}
+}
+
+func userToResource(d *schema.ResourceData, user *bigvUser) {
+	d.Set("username", user.Username)
+	d.Set("email", user.Email)
+	d.Set("admin", user.Admin)
+	d.Set("groups", user.Groups)
+}
+
+func resourceBigvUserCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	user := &bigvUser{}
+	if _, err := bigvClient.doJson("POST", bigvUsersUrl(bigvClient), userFromResource(d), user); err != nil {
+		return err
+	}
+
+	if user.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for user %s", d.Get("username"))
+	}
+
+	d.SetId(strconv.Itoa(user.Id))
+
+	log.Printf("[DEBUG] Created BigV user, Id: %s", d.Id())
+
+	return resourceBigvUserRead(d, meta)
+}
+
+func resourceBigvUserRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	user := &bigvUser{}
+	status, err := bigvClient.doJson("GET", bigvUsersUrl(bigvClient)+"/"+d.Id(), nil, user)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] User %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	userToResource(d, user)
+
+	return nil
+}
+
+func resourceBigvUserUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if _, err := bigvClient.doJson("PUT", bigvUsersUrl(bigvClient)+"/"+d.Id(), userFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvUserRead(d, meta)
+}
+
+func resourceBigvUserDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("DELETE", bigvUsersUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	return err
+}
@@ -0,0 +1,179 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvVlan struct {
+	Id      int    `json:"id,omitempty"`
+	VlanNum int    `json:"num,omitempty"`
+	Name    string `json:"name,omitempty"`
+	// An empty description is sent too, or a removed one would stay in bigv
+	Description string `json:"description"`
+
+	// Read Attributes
+	AttachedVmCount int `json:"virtual_machine_count,omitempty"`
+}
+
+func resourceBigvVlan() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvVlanCreate,
+		Read:   resourceBigvVlanRead,
+		Update: resourceBigvVlanUpdate,
+		Delete: resourceBigvVlanDelete,
+
+		Schema: map[string]*schema.Schema{
+			"vlan_num": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IntBetween(1, 4094),
+				Description:  "The VLAN number for bigv_nic's vlan_num. bigv picks a free one if not set",
+			},
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Only the group's VMs can join the VLAN. Any of the account's can without this",
+			},
+			"attached_vm_count": &schema.Schema{
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+// bigvVlansUrl
+// VLANs belong to a group, or to the whole account
+func bigvVlansUrl(d *schema.ResourceData, bigvClient *client) string {
+	if group := d.Get("group").(string); group != "" {
+		return fmt.Sprintf("%s/accounts/%s/groups/%s/vlans",
+			bigvClient.apiUri(),
+			bigvClient.account,
+			group,
+		)
+	}
+
+	return fmt.Sprintf("%s/accounts/%s/vlans",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func resourceBigvVlanCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vlan := &bigvVlan{}
+	_, err := bigvClient.doJson("POST", bigvVlansUrl(d, bigvClient), bigvVlan{
+		VlanNum:     d.Get("vlan_num").(int),
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}, vlan)
+	if err != nil {
+		return err
+	}
+
+	if vlan.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for VLAN %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(vlan.Id))
+
+	log.Printf("[DEBUG] Created BigV VLAN %d, Id: %s", vlan.VlanNum, d.Id())
+
+	return resourceBigvVlanRead(d, meta)
+}
+
+func resourceBigvVlanRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vlan, err := readBigvVlan(d, bigvClient)
+	if err != nil {
+		return err
+	}
+	if vlan == nil {
+		log.Printf("[WARN] VLAN %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("vlan_num", vlan.VlanNum)
+	d.Set("name", vlan.Name)
+	d.Set("description", vlan.Description)
+	d.Set("attached_vm_count", vlan.AttachedVmCount)
+
+	return nil
+}
+
+// readBigvVlan
+// nil without an error when bigv doesn't have it
+func readBigvVlan(d *schema.ResourceData, bigvClient *client) (*bigvVlan, error) {
+	vlan := &bigvVlan{}
+	status, err := bigvClient.doJson("GET", bigvVlansUrl(d, bigvClient)+"/"+d.Id(), nil, vlan)
+	if status == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return vlan, nil
+}
+
+func resourceBigvVlanUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	_, err := bigvClient.doJson("PUT", bigvVlansUrl(d, bigvClient)+"/"+d.Id(), bigvVlan{
+		Name:        d.Get("name").(string),
+		Description: d.Get("description").(string),
+	}, nil)
+	if err != nil {
+		return err
+	}
+
+	return resourceBigvVlanRead(d, meta)
+}
+
+// resourceBigvVlanDelete
+// Pulling the VLAN out from under VMs would cut them off, so that's refused
+func resourceBigvVlanDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vlan, err := readBigvVlan(d, bigvClient)
+	if err != nil {
+		return err
+	}
+	if vlan == nil {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+
+	if vlan.AttachedVmCount > 0 {
+		return fmt.Errorf("VLAN %d still has %d VMs attached, remove their network interfaces first", vlan.VlanNum, vlan.AttachedVmCount)
+	}
+
+	status, err := bigvClient.doJson("DELETE", bigvVlansUrl(d, bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,70 @@
+package bigv
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestVlanRoundTrip(t *testing.T) {
+	vlan := `{"id":3,"num":100,"name":"backend","description":"","virtual_machine_count":0}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvVlan(), map[string]interface{}{
+		"name":  "backend",
+		"group": "staging",
+	}, nil, []testBigvCall{
+		{"POST", "/accounts/myaccount/groups/staging/vlans", 0, vlan},
+		{"GET", "/accounts/myaccount/groups/staging/vlans/3", 0, vlan},
+		{"PUT", "/accounts/myaccount/groups/staging/vlans/3", 0, vlan},
+		{"GET", "/accounts/myaccount/groups/staging/vlans/3", 0, vlan},
+		// Checking nothing's attached
+		{"GET", "/accounts/myaccount/groups/staging/vlans/3", 0, vlan},
+		{"DELETE", "/accounts/myaccount/groups/staging/vlans/3", http.StatusNoContent, ""},
+	})
+
+	// bigv picks the number, and the empty description is still sent
+	testBigvSent(t, "create", sent[0], map[string]interface{}{"name": "backend", "description": ""})
+	testBigvSent(t, "update", sent[2], map[string]interface{}{"name": "backend", "description": ""})
+
+	if d.Id() != "3" || d.Get("vlan_num") != 100 {
+		t.Errorf("expected VLAN 3 numbered 100, got %s numbered %v", d.Id(), d.Get("vlan_num"))
+	}
+}
+
+func TestVlanAccountWide(t *testing.T) {
+	vlan := `{"id":3,"num":100,"name":"backend"}`
+
+	testBigvRoundTrip(t, resourceBigvVlan(), map[string]interface{}{
+		"name":     "backend",
+		"vlan_num": 100,
+	}, nil, []testBigvCall{
+		{"POST", "/accounts/myaccount/vlans", 0, vlan},
+		{"GET", "/accounts/myaccount/vlans/3", 0, vlan},
+		{"PUT", "/accounts/myaccount/vlans/3", 0, vlan},
+		{"GET", "/accounts/myaccount/vlans/3", 0, vlan},
+		{"GET", "/accounts/myaccount/vlans/3", 0, vlan},
+		{"DELETE", "/accounts/myaccount/vlans/3", http.StatusNoContent, ""},
+	})
+}
+
+func TestVlanDeleteRefusedWhileAttached(t *testing.T) {
+	c, server, _, check := testBigvCalls(t, []testBigvCall{
+		{"GET", "/accounts/myaccount/vlans/3", 0, `{"id":3,"num":100,"name":"backend","virtual_machine_count":2}`},
+	})
+	defer server.Close()
+
+	d := schema.TestResourceDataRaw(t, resourceBigvVlan().Schema, map[string]interface{}{"name": "backend"})
+	d.SetId("3")
+
+	err := resourceBigvVlanDelete(d, c)
+	if err == nil || !strings.Contains(err.Error(), "2 VMs attached") {
+		t.Fatalf("expected deleting a VLAN in use to be refused, got %v", err)
+	}
+	check()
+}
+
+func TestVlanNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvVlan(), map[string]interface{}{"name": "backend"}, "3")
+}
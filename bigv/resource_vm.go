@@ -2,20 +2,25 @@ package bigv
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"math"
-	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"golang.org/x/crypto/ssh"
 )
 
@@ -25,6 +30,7 @@ const (
 	vmCheckInterval    = 5
 	waitForProvisioned = 1 + iota
 	waitForPowered     = 1 + iota
+	waitForHalted      = 1 + iota
 )
 
 type bigvVm struct {
@@ -34,37 +40,65 @@ type bigvVm struct {
 	Memory       int    `json:"memory,omitempty"`
 	Hostname     string `json:"hostname,omitempty"`
 	Distribution string `json:"last_imaged_with,omitempty"`
+	Imager       string `json:"last_imager,omitempty"`
 	Power        bool   `json:"power_on"`
 	Reboot       bool   `json:"autoreboot_on"`
 	Group        string `json:"group,omitempty"`
 	GroupId      int    `json:"group_id,omitempty"`
 	Zone         string `json:"zone_name,omitempty"`
+	ConfigDrive  bool   `json:"config_drive,omitempty"`
+	Deleted      bool   `json:"deleted,omitempty"`
+	// A pointer, so that updates can clear it
+	KernelCmdline *string `json:"kernel_cmdline,omitempty"`
 }
 
 type bigvDisc struct {
+	Id           int    `json:"id,omitempty"`
 	Label        string `json:"label,omitempty"`
 	StorageGrade string `json:"storage_grade,omitempty"`
 	Size         int    `json:"size,omitempty"`
+
+	// Read Attributes
+	ProvisionedIops int `json:"provisioned_iops,omitempty"`
 }
 
+// The storage grades bigv sells discs in
+var storageGrades = []string{"sata", "ssd", "archive"}
+
+// What a VM gets without any disc blocks
+const defaultDiscSize = 25600
+const defaultStorageGrade = "sata"
+
 type bigvImage struct {
 	Distribution    string `json:"distribution,omitempty"`
 	RootPassword    string `json:"root_password,omitempty"`
 	SshPublicKey    string `json:"ssh_public_key,omitempty"`
 	FirstBootScript string `json:"firstboot_script,omitempty"`
+	PublicKeyOnly   bool   `json:"public_key_only,omitempty"`
 }
 
 type bigvIps struct {
 	// Create attributes
-	Ipv4 string `json:"ipv4,omitempty"`
-	Ipv6 string `json:"ipv6,omitempty"`
+	Ipv4   string `json:"ipv4,omitempty"`
+	Ipv6   string `json:"ipv6,omitempty"`
+	IpPool string `json:"ip_pool,omitempty"`
 }
 
 type bigvNic struct {
+	Id      int    `json:"id,omitempty"`
+	Label   string `json:"label,omitempty"`
+	VlanNum int    `json:"vlan_num,omitempty"`
+
+	// Create asks for these ips, and reads get back what was allocated
+	Ips []string `json:"ips,omitempty"`
+
 	// Read Attributes
-	Label string   `json:"label,omitempty"`
-	Ips   []string `json:"ips,omitempty"`
-	Mac   string   `json:"mac,omitempty"`
+	Mac string `json:"mac,omitempty"`
+}
+
+type bigvIp struct {
+	Ip   string `json:"ip,omitempty"`
+	Rdns string `json:"rdns,omitempty"`
 }
 
 type bigvServer struct {
@@ -87,6 +121,21 @@ func resourceBigvVM() *schema.Resource {
 		Update: resourceBigvVMUpdate,
 		Delete: resourceBigvVMDelete,
 		Exists: resourceBigvVMExists,
+		Importer: &schema.ResourceImporter{
+			State: resourceBigvVMImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(waitForVM * time.Second),
+			Update: schema.DefaultTimeout(waitForVM * time.Second),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		CustomizeDiff: resourceBigvVMCustomizeDiff,
+
+		SchemaVersion: 3,
+		MigrateState:  resourceBigvVMMigrateState,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -96,36 +145,96 @@ func resourceBigvVM() *schema.Resource {
 			"group": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
+				Computed:    true,
 				ForceNew:    true,
-				Default:     "default",
-				Description: "bigv group name for the VM. Defaults to default",
+				Description: "bigv group name for the VM. Defaults to the provider's default_group. Changing it recreates the VM",
 			},
 			"group_id": &schema.Schema{
 				Type:     schema.TypeInt,
 				Computed: true,
 			},
-			"zone": &schema.Schema{
+			"resource_path": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The VM's path in the bigv API",
+			},
+			"description": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
-				ForceNew:    true,
-				Default:     "york",
-				Description: "bigv zone to put the VM in. Defaults to york",
+				Description: "Notes on what the VM is for. Only kept in terraform's state, bigv doesn't store it",
+			},
+			"ipv4_ptr": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The reverse DNS PTR record of the VM's ipv4 address",
+			},
+			"hostname": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The hostname bigv gave the VM, which can differ from its name",
+			},
+			"mac_address": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The MAC address of the VM's first network interface",
+			},
+			"fqdn": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The VM's fully qualified domain name in bigv, e.g. web01.default.myaccount.uk0.bigv.io",
+			},
+			"state": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "What the VM is doing: provisioning, running, halted or deleted",
+			},
+			"billing_code": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The billing code of the VM's group, for cost allocation",
+			},
+			"zone": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(bigvZones, false),
+				Description:  "bigv zone to put the VM in. Defaults to the provider's default_zone",
 			},
 			"ipv4": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIPv4,
 			},
 			"ipv6": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Computed: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validateIPv6,
+			},
+			"ip_pool": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"ipv4"},
+				Description:   "The name of a bigv_ip_pool to allocate the VM's ipv4 address from",
+			},
+			"security_group_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Ids of bigv_security_groups whose rules make up the VM's firewall",
 			},
 			"os": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "vivid",
-				ForceNew: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ForceNew:     true,
+				ValidateFunc: validateBigvOsName,
+				StateFunc:    lowercase,
+				Description:  "Short name of the distribution to image, or none to leave the VM blank",
 			},
 			"cores": &schema.Schema{
 				Type:         schema.TypeInt,
@@ -139,15 +248,52 @@ func resourceBigvVM() *schema.Resource {
 				Computed:     true,
 				ComputedWhen: []string{"cores"},
 			},
-			"disc_size": &schema.Schema{
-				Type:     schema.TypeInt,
-				Optional: true,
-				Default:  "25600",
-				ForceNew: true,
+			"disc": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "The VM's discs, the first is the one booted from. Defaults to a single 25GiB sata disc",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Required: true,
+							ForceNew: true,
+						},
+						"size": &schema.Schema{
+							Type:        schema.TypeInt,
+							Optional:    true,
+							Computed:    true,
+							ForceNew:    true,
+							Description: "Disc size in MiB. Defaults to the provider's default_disc_size",
+						},
+						"storage_grade": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ForceNew:     true,
+							ValidateFunc: validation.StringInSlice(storageGrades, false),
+							Description:  "The disc's storage grade: sata, ssd or archive. Defaults to the provider's default_storage_grade",
+						},
+						"provisioned_iops": &schema.Schema{
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The IOPS ceiling of the disc's storage grade, where bigv has one",
+						},
+					},
+				},
 			},
 			"root_password": &schema.Schema{
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The generated root password. Hidden from plans, but still in the state for provisioners",
+			},
+			"image_installer": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The installer version bigv last imaged the VM with",
 			},
 			"power_on": &schema.Schema{
 				Type:        schema.TypeBool,
@@ -162,24 +308,386 @@ func resourceBigvVM() *schema.Resource {
 				Description: "Whether or not to reboot the VM when the power_on is turned off",
 			},
 			"ssh_public_key": &schema.Schema{
-				Type:        schema.TypeString,
+				Type:        schema.TypeList,
 				Optional:    true,
-				Description: "One or more ssh public keys to put on the machine. Will only work if os is not core",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "ssh public keys to put on the machine. Will only work if os is not core",
+			},
+			"ssh_key_names": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of ssh keys kept in the bigv account, e.g. by bigv_ssh_key, to put on the machine",
 			},
 			"firstboot_script": &schema.Schema{
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "A script to be executed on first boot arbitrarily",
 			},
+			"dns_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 3,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateIP,
+				},
+				Description: "DNS resolvers to configure on first boot",
+			},
+			"ntp_servers": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validateHostOrIP,
+				},
+				DiffSuppressFunc: suppressListOrderDiff,
+				Description:      "NTP servers to configure on first boot",
+			},
+			"locale": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultLocale,
+				ForceNew:     true,
+				ValidateFunc: validateLocale,
+				Description:  "System locale, set on first boot",
+			},
+			"kernel_cmdline": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateKernelCmdline,
+				Description:  "Extra kernel command line parameters. Changing them restarts the VM",
+			},
+			"additional_nics": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Extra network interfaces to attach once the VM is created",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"vlan_num": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"network_interface": &schema.Schema{
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				ForceNew:      true,
+				ConflictsWith: []string{"additional_nics"},
+				Description:   "All of the VM's network interfaces, the first is the one created with the VM",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Optional: true,
+							Computed: true,
+						},
+						"vlan_num": &schema.Schema{
+							Type:     schema.TypeInt,
+							Optional: true,
+							Computed: true,
+						},
+						"ipv4": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateIPv4,
+						},
+						"ipv6": &schema.Schema{
+							Type:         schema.TypeString,
+							Optional:     true,
+							Computed:     true,
+							ValidateFunc: validateIPv6,
+						},
+						"mac": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"all_network_interfaces": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Every network interface bigv has for the VM, including ones attached outside it",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vlan_num": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"ipv4": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ipv6": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"mac": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"all_discs": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Every disc bigv has for the VM, including ones attached by bigv_disc",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"label": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"storage_grade": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provisioned_iops": &schema.Schema{
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"public_key_auth_only": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Disable root password auth, leaving only ssh_public_key",
+			},
+			"config_drive": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Whether to give cloud-init a config drive. Those images usually only allow ssh key auth",
+			},
+			"destroy_mode": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "purge",
+				ValidateFunc: validation.StringInSlice([]string{"purge", "delete", "power_off"}, false),
+				Description:  "What destroying does to the VM: purge, delete (recoverable for 24h) or power_off",
+			},
+			"force_shutdown": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Power the VM off and wait for it to halt before destroying it",
+			},
+			"purge": &schema.Schema{
+				Type:          schema.TypeBool,
+				Optional:      true,
+				Default:       true,
+				ConflictsWith: []string{"destroy_mode"},
+				Description:   "Whether destroying purges the VM's discs. false is the same as destroy_mode delete",
+			},
+			"provisioning_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      waitForVM,
+				ValidateFunc: validation.IntAtLeast(1),
+				Deprecated:   "Use timeouts { create = ... } instead",
+				Description:  "Seconds to wait for the VM to be imaged, powered and listening on ssh",
+			},
+			"api_timeout_override": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Timeout in seconds for individual bigv API requests about this VM. 0 uses the provider default",
+			},
 		},
 	}
 }
 
+// currentDistributions
+// Distributions still getting security updates, along with none for no os at all.
+// Anything bigv adds needs to go here too, or it'll fail validation.
+var currentDistributions = []string{"bullseye", "bookworm", "focal", "jammy", "noble", "none"}
+
+// knownDistributions
+// Every os we'll accept, end of life or not
+func knownDistributions() []string {
+	distributions := append([]string{}, currentDistributions...)
+	for distribution := range eolDistributions {
+		distributions = append(distributions, distribution)
+	}
+	sort.Strings(distributions)
+
+	return distributions
+}
+
+// validateBigvOsName
+// Any os we know of, in any case. End of life ones are allowed, but warned about
+func validateBigvOsName(v interface{}, k string) (ws []string, es []error) {
+	ws, es = validation.StringInSlice(knownDistributions(), true)(v, k)
+	if warning := eolWarning(v.(string)); warning != "" {
+		ws = append(ws, warning)
+	}
+	return
+}
+
+// eolWarning
+// Why an os is worth a second thought, empty if it's still supported
+func eolWarning(os string) string {
+	os = strings.ToLower(os)
+	if eol, ok := eolDistributions[os]; ok {
+		return fmt.Sprintf("os %s has been end of life since %s and no longer gets security updates", os, eol)
+	}
+
+	return ""
+}
+
+// lowercase
+// os names are case insensitive to us, but not to bigv
+func lowercase(v interface{}) string {
+	return strings.ToLower(v.(string))
+}
+
+// bigvZones
+// The zones VMs can go in. The bigv_zones data source has the current list if these fall behind.
+var bigvZones = []string{"york", "manchester"}
+
+// eolDistributions
+// Distributions we'll still image, but which have stopped getting security updates,
+// with when they stopped. Update this as more go end of life.
+var eolDistributions = map[string]string{
+	"squeeze": "2016-02",
+	"wheezy":  "2018-05",
+	"jessie":  "2020-06",
+	"stretch": "2022-06",
+	"buster":  "2024-06",
+	"precise": "2017-04",
+	"trusty":  "2019-04",
+	"utopic":  "2015-07",
+	"vivid":   "2016-02",
+	"wily":    "2016-07",
+	"xenial":  "2021-04",
+	"bionic":  "2023-05",
+	"centos6": "2020-11",
+	"centos7": "2024-06",
+}
+
+// resourceBigvVMCustomizeDiff
+// Checks the os with bigv, now we have a client to ask.
+// Changing group replaces the VM, which is easy to miss in a long plan, so that's logged as well.
+func resourceBigvVMCustomizeDiff(d *schema.ResourceDiff, meta interface{}) error {
+	if d.HasChange("os") {
+		if err := validateBigvOs(d.Get("os").(string), meta.(*client)); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() != "" && d.HasChange("group") {
+		old, new := d.GetChange("group")
+		log.Printf("[WARN] Warning: changing 'group' of VM %s from %s to %s will destroy and recreate the VM. All data will be lost unless you have backups.",
+			d.Id(),
+			old,
+			new,
+		)
+		return d.ForceNew("group")
+	}
+
+	return nil
+}
+
+// validateBigvOs
+// bigv happily creates a VM with an os it doesn't have, then fails imaging it.
+// Costs an API call per plan, so only if the provider asks for it.
+func validateBigvOs(os string, bigvClient *client) error {
+	// Empty when it isn't known until apply
+	if !bigvClient.validateOs || os == "" || os == "none" {
+		return nil
+	}
+
+	distributions, err := readBigvDistributions(bigvClient)
+	if err != nil {
+		return err
+	}
+
+	distribution, ok := findBigvDistribution(distributions, os)
+	if !ok {
+		return fmt.Errorf("bigv has no os %s, see the bigv_distributions data source for those it has", os)
+	}
+	if distribution.Deprecated {
+		log.Printf("[WARN] Warning: os %s is deprecated by bigv.", os)
+	}
+
+	return nil
+}
+
 var createPipeline sync.Mutex
 
+// lockCreatePipeline
+// Holds back VM creates as create_pipeline_mode says to.
+// Returns the func to let the next one through, or an error if we're interrupted or time out waiting.
+func lockCreatePipeline(ctx context.Context, bigvClient *client) (func(), error) {
+	switch bigvClient.createPipelineMode {
+	case "serial":
+		createPipeline.Lock()
+		return createPipeline.Unlock, nil
+	case "smart":
+		select {
+		case bigvClient.creates <- struct{}{}:
+			return func() { <-bigvClient.creates }, nil
+		case <-ctx.Done():
+			return nil, waitError(ctx, "A turn in the create pipeline")
+		}
+	}
+
+	// parallel
+	return func() {}, nil
+}
+
 func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 	bigvClient := meta.(*client)
 
+	// Every wait for the VM shares the one deadline
+	ctx, cancel := context.WithTimeout(bigvClient.stopContext(), provisioningTimeout(d, schema.TimeoutCreate))
+	defer cancel()
+
+	rootPassword, err := randomPassword()
+	if err != nil {
+		return err
+	}
+
+	sshKeys, err := sshPublicKeys(d, bigvClient)
+	if err != nil {
+		return err
+	}
+
+	// Schema defaults can't see the provider's config, so unset ones are filled in here
+	if d.Get("group").(string) == "" {
+		d.Set("group", bigvClient.defaultGroup)
+	}
+	if d.Get("zone").(string) == "" {
+		d.Set("zone", bigvClient.defaultZone)
+	}
+	if d.Get("os").(string) == "" {
+		d.Set("os", bigvClient.defaultOs)
+	}
+
 	vm := bigvVMCreate{
 		VirtualMachine: bigvVm{
 			Name:   d.Get("name").(string),
@@ -189,20 +697,28 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 			Reboot: d.Get("reboot").(bool),
 			Group:  d.Get("group").(string),
 			Zone:   d.Get("zone").(string),
+
+			ConfigDrive: d.Get("config_drive").(bool),
 		},
-		Discs: []bigvDisc{{
-			Label:        "root",
-			StorageGrade: "sata",
-			Size:         d.Get("disc_size").(int),
-		}},
+		Discs: discsFromResource(d, bigvClient),
 		Image: bigvImage{
 			Distribution:    d.Get("os").(string),
-			RootPassword:    randomPassword(),
-			SshPublicKey:    d.Get("ssh_public_key").(string),
-			FirstBootScript: d.Get("firstboot_script").(string),
+			RootPassword:    rootPassword,
+			SshPublicKey:    sshKeys,
+			FirstBootScript: firstbootScript(d),
+			PublicKeyOnly:   d.Get("public_key_auth_only").(bool),
 		},
 	}
 
+	// No password at all if we're only allowing keys
+	if vm.Image.PublicKeyOnly {
+		vm.Image.RootPassword = ""
+	}
+
+	if cmdline := d.Get("kernel_cmdline").(string); cmdline != "" {
+		vm.VirtualMachine.KernelCmdline = &cmdline
+	}
+
 	// If no ipv* is set then let bigv allocate it itself
 	// The json for ip must be nil
 	if ip := d.Get("ipv4"); ip != nil && ip.(string) != "" {
@@ -218,18 +734,35 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 		vm.Ips.Ipv6 = ip.(string)
 	}
 
+	// Otherwise the first network_interface can ask for them
+	nics := d.Get("network_interface").([]interface{})
+	if vm.Ips == nil && len(nics) > 0 {
+		nic := nics[0].(map[string]interface{})
+		if nic["ipv4"].(string) != "" || nic["ipv6"].(string) != "" {
+			vm.Ips = &bigvIps{
+				Ipv4: nic["ipv4"].(string),
+				Ipv6: nic["ipv6"].(string),
+			}
+		}
+	}
+
+	// Or bigv can pick from one of our pools
+	if pool := d.Get("ip_pool").(string); pool != "" {
+		if vm.Ips == nil {
+			vm.Ips = &bigvIps{}
+		}
+		vm.Ips.IpPool = pool
+	}
+
 	// Make sure the root password gets stored in d
 	d.Set("root_password", vm.Image.RootPassword)
 
 	// Connection information
-	connInfo := map[string]string{
-		"type":     "ssh",
-		"password": vm.Image.RootPassword,
-	}
+	host := ""
 	if vm.Ips != nil {
-		connInfo["host"] = vm.Ips.Ipv4
+		host = vm.Ips.Ipv4
 	}
-	d.SetConnInfo(connInfo)
+	d.SetConnInfo(vmConnInfo(d, host))
 
 	if err := vm.VirtualMachine.computeCoresToMemory(); err != nil {
 		return err
@@ -239,6 +772,14 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 		return errors.New("Cannot deploy ssh public keys with an os of 'none'. Please use a provisioner instead")
 	}
 
+	if vm.VirtualMachine.ConfigDrive && vm.Image.SshPublicKey == "" {
+		return errors.New("config_drive images only allow ssh key auth, so ssh_public_key or ssh_key_names must be set")
+	}
+
+	if vm.Image.PublicKeyOnly && vm.Image.SshPublicKey == "" {
+		return errors.New("public_key_auth_only needs ssh_public_key or ssh_key_names to be set, otherwise nobody can log in")
+	}
+
 	body, err := json.Marshal(vm)
 	if err != nil {
 		return err
@@ -246,13 +787,12 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 
 	// VM create uses a bigger path
 	url := fmt.Sprintf("%s/accounts/%s/groups/%s/vm_create",
-		bigvUri,
+		bigvClient.apiUri(),
 		bigvClient.account,
 		vm.VirtualMachine.Group, // this will be group name
 	)
 
 	log.Printf("[DEBUG] Requesting VM create: %s", url)
-	log.Printf("[DEBUG] VM profile: %s", body)
 
 	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(body))
 
@@ -261,9 +801,8 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 	// That might be an ip allocation issue, and specifying both ips might
 	// fix it, but that's untested. For now waiting for them to confirm we
 	// can lift this restriction.
-	createPipeline.Lock()
-	resp, err := bigvClient.do(req)
-	createPipeline.Unlock()
+	// create_pipeline_mode lets accounts where it's fixed go faster.
+	resp, err := requestBigvVMCreate(ctx, d, bigvClient, req)
 	if err != nil {
 		return err
 	}
@@ -275,7 +814,7 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 
 	if resp.StatusCode != http.StatusAccepted {
 		body, _ := ioutil.ReadAll(resp.Body)
-		return fmt.Errorf("Create VM status %d from bigv: %s", resp.StatusCode, body)
+		return fmt.Errorf("Create VM failed: %s", bigvError(resp.StatusCode, body))
 	}
 
 	d.Partial(true)
@@ -288,21 +827,46 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	// wait for state also sets up the resource from the read state we get back
-	if err := waitForBigvState(d, bigvClient, waitForProvisioned); err != nil {
+	if err := waitForBigvState(ctx, d, bigvClient, waitForProvisioned, bigvClient.provisionPollInterval); err != nil {
 		return err
 	}
 
 	log.Printf("[DEBUG] Created BigV VM, Id: %s", d.Id())
 
+	setResourcePath(d, bigvClient.account)
+
+	// Everything past the first network_interface is attached the same way
+	extraNics := d.Get("additional_nics").([]interface{})
+	if len(nics) > 1 {
+		extraNics = nics[1:]
+	}
+
+	if len(extraNics) > 0 {
+		if err := attachBigvNics(d, bigvClient, extraNics); err != nil {
+			return err
+		}
+
+		// Read the nics back in
+		if err := waitForBigvState(ctx, d, bigvClient, waitForProvisioned, bigvClient.provisionPollInterval); err != nil {
+			return err
+		}
+	}
+
+	if d.Get("security_group_ids").(*schema.Set).Len() > 0 {
+		if err := setBigvVMSecurityGroups(d, bigvClient); err != nil {
+			return err
+		}
+	}
+
 	// If we expect it to be turned on, wait for it to powered
 	if vm.VirtualMachine.Power == true {
-		if err := waitForBigvState(d, bigvClient, waitForPowered); err != nil {
+		if err := waitForBigvState(ctx, d, bigvClient, waitForPowered, bigvClient.provisionPollInterval); err != nil {
 			return err
 		}
 
 		// This assumes all distributions will listen on public ssh
 		if vm.Image.Distribution != "none" {
-			if err := waitForVmSsh(d); err != nil {
+			if err := waitForVmSsh(ctx, d, bigvClient.provisionPollInterval); err != nil {
 				return err
 			}
 		}
@@ -310,37 +874,97 @@ func resourceBigvVMCreate(d *schema.ResourceData, meta interface{}) error {
 
 	d.Partial(false)
 
+	if err := readBigvVMGroup(d, bigvClient, bigvClient.account); err != nil {
+		return err
+	}
+
+	return readBigvIPv4Ptr(d, bigvClient)
+
+}
+
+// attachBigvNics
+// vm_create only gives us the one nic, so any others get added afterwards
+func attachBigvNics(d *schema.ResourceData, bigvClient *client, nics []interface{}) error {
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s/nics",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Id(),
+	)
+
+	for _, n := range nics {
+		nic := n.(map[string]interface{})
+
+		body, err := json.Marshal(bigvNic{
+			Label:   nic["label"].(string),
+			VlanNum: nic["vlan_num"].(int),
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] Requesting NIC create: %s", url)
+
+		req, _ := http.NewRequest("POST", url, bytes.NewBuffer(body))
+
+		resp, err := vmDo(d, bigvClient, req)
+		if err != nil {
+			return err
+		}
+
+		log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+			body, _ := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			return fmt.Errorf("Create NIC status %d from bigv: %s", resp.StatusCode, body)
+		}
+		resp.Body.Close()
+	}
+
 	return nil
+}
+
+// requestBigvVMCreate
+// Only the create request itself goes through the pipeline, not the wait for imaging.
+// Deferred so the pipeline is let go however the request ends.
+func requestBigvVMCreate(ctx context.Context, d *schema.ResourceData, bigvClient *client, req *http.Request) (*http.Response, error) {
+	unlockCreatePipeline, err := lockCreatePipeline(ctx, bigvClient)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockCreatePipeline()
 
+	return vmDo(d, bigvClient, req)
 }
 
 // waitForBigvState
 // Obviously wait for a state
 // Also sets up the resource from the state read
-func waitForBigvState(d *schema.ResourceData, bigvClient *client, waitFor int) error {
-	url := fmt.Sprintf("%s/virtual_machines/%s?view=overview",
-		bigvUri,
-		d.Get("name"),
-	)
+func waitForBigvState(ctx context.Context, d *schema.ResourceData, bigvClient *client, waitFor int, interval time.Duration) error {
+	url := vmOverviewUrl(d, bigvClient)
 
 	log.Printf("[DEBUG] VM Health Check: %s", url)
 	req, _ := http.NewRequest("GET", url, nil)
 
+	// Stopped on the way out, so nothing is left ticking
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	var body []byte
 	for {
 		select {
-		case <-time.After(waitForVM * time.Second):
-			return fmt.Errorf("VM state didn't happen in %d seconds", waitForVM)
-		case <-time.Tick(vmCheckInterval * time.Second):
-			resp, err := bigvClient.do(req)
+		case <-ctx.Done():
+			return waitError(ctx, "VM state")
+		case <-ticker.C:
+			resp, err := vmDo(d, bigvClient, req)
 			if err != nil {
 				return fmt.Errorf("Error checking on VM health: %s", err)
 			}
 
-			// Always close the body when done
-			defer resp.Body.Close()
-
+			// Close as we go, a defer would hold every poll's body open until we're done
 			body, _ = ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
 
 			log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
 			// No matter what, update everything comes from the state
@@ -348,6 +972,11 @@ func waitForBigvState(d *schema.ResourceData, bigvClient *client, waitFor int) e
 				return err
 			}
 
+			// bigv says Accepted until it's finished setting the VM up
+			if resp.StatusCode == http.StatusAccepted {
+				d.Set("state", "provisioning")
+			}
+
 			if resp.StatusCode == http.StatusOK {
 				if waitFor == waitForProvisioned {
 					log.Println("[DEBUG] VM is Up and HTTP OK")
@@ -359,6 +988,9 @@ func waitForBigvState(d *schema.ResourceData, bigvClient *client, waitFor int) e
 				case waitFor == waitForPowered && d.Get("power_on").(bool):
 					log.Println("[DEBUG] VM is powered")
 					return nil
+				case waitFor == waitForHalted && !d.Get("power_on").(bool):
+					log.Println("[DEBUG] VM is halted")
+					return nil
 				}
 			}
 
@@ -367,12 +999,31 @@ func waitForBigvState(d *schema.ResourceData, bigvClient *client, waitFor int) e
 			}
 		}
 	}
+}
+
+// provisioningTimeout
+// How long the create or update, given as schema.TimeoutCreate or schema.TimeoutUpdate, may wait for the VM.
+// provisioning_timeout came first, so still wins if it's been changed.
+func provisioningTimeout(d *schema.ResourceData, key string) time.Duration {
+	if seconds := d.Get("provisioning_timeout").(int); seconds != waitForVM {
+		return time.Duration(seconds) * time.Second
+	}
+
+	return d.Timeout(key)
+}
+
+// waitError
+// Why a wait ended early, running out of time or being interrupted
+func waitError(ctx context.Context, what string) error {
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("%s didn't happen before the timeout", what)
+	}
 
-	return fmt.Errorf("VM state didn't happen in %d seconds", waitForVM)
+	return ctx.Err()
 }
 
 // Simply waits for ssh to come up
-func waitForVmSsh(d *schema.ResourceData) error {
+func waitForVmSsh(ctx context.Context, d *schema.ResourceData, interval time.Duration) error {
 	log.Printf("[DEBUG] Waiting for VM ssh: %s", d.Get("name"))
 
 	config := &ssh.ClientConfig{
@@ -383,13 +1034,27 @@ func waitForVmSsh(d *schema.ResourceData) error {
 		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
 	}
 
+	// Some VMs only take the ssh keys, and we don't have the private half.
+	// So being refused authentication is as alive as they get
+	keyOnly := keyAuthOnly(d)
+	if keyOnly {
+		config.Auth = nil
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
 	for {
 		select {
-		case <-time.After(waitForVM * time.Second):
-			return fmt.Errorf("VM ssh wasn't up in %d seconds", waitForVM)
-		case <-time.Tick(vmCheckInterval * time.Second):
+		case <-ctx.Done():
+			return waitError(ctx, "VM ssh")
+		case <-ticker.C:
 			conn, err := ssh.Dial("tcp", fmt.Sprintf("%s:22", d.Get("ipv4")), config)
 			if err != nil {
+				if keyOnly && strings.Contains(err.Error(), "unable to authenticate") {
+					log.Println("[DEBUG] SSH alive and only taking keys")
+					return nil
+				}
 				if strings.Contains(err.Error(), "connection refused") {
 					log.Println("[DEBUG] SSH isn't up yet")
 					continue
@@ -403,24 +1068,35 @@ func waitForVmSsh(d *schema.ResourceData) error {
 			return nil
 		}
 	}
-
-	return errors.New("Ssh wait should never get here")
 }
 
 func resourceBigvVMUpdate(d *schema.ResourceData, meta interface{}) error {
 	bigvClient := meta.(*client)
 
-	vm := bigvVm{}
+	ctx, cancel := context.WithTimeout(bigvClient.stopContext(), provisioningTimeout(d, schema.TimeoutUpdate))
+	defer cancel()
+
+	// Security groups are attached separately, and don't touch the VM itself
+	if d.HasChange("security_group_ids") {
+		if err := setBigvVMSecurityGroups(d, bigvClient); err != nil {
+			return err
+		}
+	}
 
-	if d.HasChange("power_on") {
-		vm.Power = d.Get("power_on").(bool)
+	// Plenty of attributes, like description, only live in terraform.
+	// There's nothing to send bigv for those.
+	if !d.HasChange("power_on") && !d.HasChange("reboot") && !d.HasChange("cores") && !d.HasChange("memory") && !d.HasChange("kernel_cmdline") {
+		return nil
 	}
 
-	if d.HasChange("power_on") {
-		vm.Reboot = d.Get("reboot").(bool)
+	// power_on and reboot are always sent, so leaving them out can't power the VM off
+	vm := bigvVm{
+		Power:  d.Get("power_on").(bool),
+		Reboot: d.Get("reboot").(bool),
 	}
 
-	if d.HasChange("cores") || d.HasChange("memory") {
+	resized := d.HasChange("cores") || d.HasChange("memory")
+	if resized {
 		// Specifiy both cores and memory together always, so we can validate them.
 		vm.Cores = d.Get("cores").(int)
 		vm.Memory = d.Get("memory").(int)
@@ -436,6 +1112,17 @@ func resourceBigvVMUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 
+	if d.HasChange("kernel_cmdline") {
+		cmdline := d.Get("kernel_cmdline").(string)
+		vm.KernelCmdline = &cmdline
+
+		// The kernel only reads it on boot
+		if !d.HasChange("power_on") {
+			vm.Power = false
+			vm.Reboot = true
+		}
+	}
+
 	if err := vm.computeCoresToMemory(); err != nil {
 		return err
 	}
@@ -446,14 +1133,13 @@ func resourceBigvVMUpdate(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s",
-		bigvUri,
+		bigvClient.apiUri(),
 		bigvClient.account,
 		d.Get("group"),
 		d.Id(),
 	)
 
 	log.Printf("[DEBUG] Requesting VM update: %s", url)
-	log.Printf("[DEBUG] VM profile: %s", body)
 
 	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(body))
 	if err != nil {
@@ -461,7 +1147,7 @@ func resourceBigvVMUpdate(d *schema.ResourceData, meta interface{}) error {
 		return err
 	}
 
-	if resp, err := bigvClient.do(req); err != nil {
+	if resp, err := vmDo(d, bigvClient, req); err != nil {
 		return err
 	} else {
 
@@ -471,37 +1157,35 @@ func resourceBigvVMUpdate(d *schema.ResourceData, meta interface{}) error {
 		log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
 
 		if resp.StatusCode != http.StatusOK {
-			return fmt.Errorf("Update VM bad status from bigv: %d", resp.StatusCode)
+			body, _ := ioutil.ReadAll(resp.Body)
+			return fmt.Errorf("Update VM failed: %s", bigvError(resp.StatusCode, body))
 		}
 
-		if body, err := ioutil.ReadAll(resp.Body); err != nil {
-			return err
-		} else {
-			if err := resourceFromJson(d, body); err != nil {
-				return err
+		log.Printf("[DEBUG] Updated BigV VM, Id: %s", d.Id())
+
+		// Resizing and kernel_cmdline changes restart the VM, as does powering off with reboot on.
+		// Wait for it to come back up
+		if vm.Reboot && !vm.Power {
+			if err := waitForBigvState(ctx, d, bigvClient, waitForPowered, bigvClient.resizePollInterval); err != nil {
+				return err
 			}
 		}
 
-		log.Printf("[DEBUG] Updated BigV VM, Id: %s", d.Id())
-
-		return nil
+		// The update response leaves out plenty, like discs and ips, so read it all back
+		return resourceBigvVMRead(d, meta)
 	}
-	return nil
 }
 
 func resourceBigvVMRead(d *schema.ResourceData, meta interface{}) error {
 	bigvClient := meta.(*client)
 
-	url := fmt.Sprintf("%s/virtual_machines/%s?view=overview",
-		bigvUri,
-		d.Get("name"),
-	)
+	url := vmOverviewUrl(d, bigvClient)
 
 	log.Printf("[DEBUG] VM Read: %s", url)
 
 	req, _ := http.NewRequest("GET", url, nil)
 
-	resp, err := bigvClient.do(req)
+	resp, err := vmDo(d, bigvClient, req)
 	if err != nil {
 		return err
 	}
@@ -520,34 +1204,374 @@ func resourceBigvVMRead(d *schema.ResourceData, meta interface{}) error {
 		return ioErr
 	}
 
-	return resourceFromJson(d, body)
+	if err := resourceFromJson(d, body); err != nil {
+		return err
+	}
+
+	setResourcePath(d, bigvClient.account)
+
+	if err := readBigvVMGroup(d, bigvClient, bigvClient.account); err != nil {
+		return err
+	}
+
+	if err := readBigvVMSecurityGroups(d, bigvClient); err != nil {
+		return err
+	}
+
+	return readBigvIPv4Ptr(d, bigvClient)
+}
+
+// vmOverviewUrl
+// VMs are looked up by id. Until a create has been read back all we have is the name,
+// which bigv only understands within the account and group.
+func vmOverviewUrl(d *schema.ResourceData, bigvClient *client) string {
+	if d.Id() == "" {
+		return fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s?view=overview",
+			bigvClient.apiUri(),
+			bigvClient.account,
+			d.Get("group"),
+			d.Get("name"),
+		)
+	}
+
+	return fmt.Sprintf("%s/virtual_machines/%s?view=overview",
+		bigvClient.apiUri(),
+		d.Id(),
+	)
+}
+
+// setResourcePath
+// For anyone making their own API calls. Group ids are preferred since they never change
+func setResourcePath(d *schema.ResourceData, account string) {
+	group := d.Get("group").(string)
+	if id := d.Get("group_id").(int); id != 0 {
+		group = strconv.Itoa(id)
+	}
+
+	d.Set("resource_path", fmt.Sprintf("/accounts/%s/groups/%s/virtual_machines/%s",
+		account,
+		group,
+		d.Id(),
+	))
+}
+
+// vmDo
+// Makes a request about this VM, honouring any api_timeout_override
+func vmDo(d *schema.ResourceData, bigvClient *client, req *http.Request) (*http.Response, error) {
+	timeout := time.Duration(d.Get("api_timeout_override").(int)) * time.Second
+	return bigvClient.doWithTimeout(req, timeout)
+}
+
+// readBigvVMGroup
+// VMs only come back with their group id, so the name comes from the group.
+// The billing code belongs to the group too, so VMs just inherit it
+func readBigvVMGroup(d *schema.ResourceData, bigvClient *client, account string) error {
+	group := d.Get("group").(string)
+	if id := d.Get("group_id").(int); id != 0 {
+		group = strconv.Itoa(id)
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s",
+		bigvClient.apiUri(),
+		account,
+		group,
+	)
+
+	log.Printf("[DEBUG] Group Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Read group Bad HTTP status from bigv: %d", resp.StatusCode)
+	}
+
+	vmGroup := &bigvGroup{}
+	if err := json.NewDecoder(resp.Body).Decode(vmGroup); err != nil {
+		return err
+	}
+
+	if vmGroup.Name != "" {
+		d.Set("group", vmGroup.Name)
+	}
+	d.Set("billing_code", vmGroup.BillingCode)
+
+	// Now we've got the group's name, not just its id
+	d.Set("fqdn", vmFqdn(d.Get("name").(string), d.Get("group").(string), account, bigvClient.apiUrl))
+
+	return nil
+}
+
+// readBigvIPv4Ptr
+// bigv sets a default PTR record for every ip it hands out, which can then be changed
+func readBigvIPv4Ptr(d *schema.ResourceData, bigvClient *client) error {
+	ipv4 := d.Get("ipv4").(string)
+	if ipv4 == "" {
+		d.Set("ipv4_ptr", "")
+		return nil
+	}
+
+	ip, err := readBigvIp(bigvClient, ipv4)
+	if err != nil {
+		return err
+	}
+
+	// Not every ip is bigv's to look up, so there's no PTR record to show
+	if ip == nil {
+		d.Set("ipv4_ptr", "")
+		return nil
+	}
+
+	d.Set("ipv4_ptr", ip.Rdns)
+
+	return nil
+}
+
+// readBigvIp
+// What bigv knows about one of its ips, such as its reverse DNS.
+// nil if it doesn't know the ip at all
+func readBigvIp(bigvClient *client, address string) (*bigvIp, error) {
+	url := fmt.Sprintf("%s/ips/%s", bigvClient.apiUri(), address)
+
+	log.Printf("[DEBUG] IP Read: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+
+	// Errors come back with the response, and ips from outside bigv aren't an error
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		log.Printf("[DEBUG] IP %s not found in bigv", address)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Read IP %s Bad HTTP status from bigv: %d", address, resp.StatusCode)
+	}
+
+	ip := &bigvIp{}
+	if err := json.NewDecoder(resp.Body).Decode(ip); err != nil {
+		return nil, err
+	}
+
+	return ip, nil
+}
+
+// vmFqdn
+// bigv gives every VM a name under the API's domain, e.g. web01.default.myaccount.uk0.bigv.io
+func vmFqdn(name, group, account, apiUrl string) string {
+	domain := apiUrl
+	if u, err := url.Parse(apiUrl); err == nil && u.Hostname() != "" {
+		domain = u.Hostname()
+	}
+
+	return strings.ToLower(fmt.Sprintf("%s.%s.%s.%s", name, group, account, domain))
+}
+
+// resourceBigvVMImport
+// Imports by numeric VM id, or by account/group/name.
+// Read fills in everything bigv knows.
+// Everything else only lives in terraform, so starts at its default.
+func resourceBigvVMImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	if _, err := strconv.Atoi(d.Id()); err != nil {
+		if !strings.Contains(d.Id(), "/") {
+			return nil, fmt.Errorf("Expected a numeric VM id or account/group/name to import, got: %s", d.Id())
+		}
+
+		id, err := resolveBigvVMPath(d.Id(), meta.(*client))
+		if err != nil {
+			return nil, err
+		}
+		d.SetId(id)
+	}
+
+	for k, s := range resourceBigvVM().Schema {
+		if s.Default != nil {
+			d.Set(k, s.Default)
+		}
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// resolveBigvVMPath
+// Looks up the numeric id of the VM at account/group/name
+func resolveBigvVMPath(path string, bigvClient *client) (string, error) {
+	parts := strings.Split(path, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", fmt.Errorf("Expected account/group/name to import, e.g. myaccount/default/web01, got: %s", path)
+	}
+
+	if parts[0] != bigvClient.account {
+		return "", fmt.Errorf("Can't import %s, it's not in the provider's account %s", path, bigvClient.account)
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s",
+		bigvClient.apiUri(),
+		parts[0],
+		parts[1],
+		parts[2],
+	)
+
+	log.Printf("[DEBUG] VM Import lookup: %s", url)
+
+	req, _ := http.NewRequest("GET", url, nil)
+
+	resp, err := bigvClient.do(req)
+	if err != nil {
+		return "", err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] HTTP response Status: %s", resp.Status)
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Import VM %s Bad HTTP status from bigv: %d", path, resp.StatusCode)
+	}
+
+	vm := &bigvVm{}
+	if err := json.NewDecoder(resp.Body).Decode(vm); err != nil {
+		return "", err
+	}
+
+	if vm.Id == 0 {
+		return "", fmt.Errorf("bigv didn't return an id for VM %s", path)
+	}
+
+	return strconv.Itoa(vm.Id), nil
 }
 
 func resourceBigvVMDelete(d *schema.ResourceData, meta interface{}) error {
 	bigvClient := meta.(*client)
 
-	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s?purge=true",
-		bigvUri,
+	mode := d.Get("destroy_mode").(string)
+	if mode == "purge" && !d.Get("purge").(bool) {
+		mode = "delete"
+	}
+
+	// Dropping the VM from state is deliberate, destroy can't leave anything behind in it.
+	// bigv still has the VM and still bills for it though, so its id is logged for finding it again
+	if mode == "power_off" {
+		if err := powerOffBigvVM(d, bigvClient); err != nil {
+			return err
+		}
+
+		log.Printf("[WARN] VM %s (%s) is powered off but not deleted. It's no longer managed by terraform, and needs deleting by hand or importing again",
+			d.Id(),
+			d.Get("fqdn"),
+		)
+		d.SetId("")
+		return nil
+	}
+
+	// Deleting a running VM is like pulling its plug, so stop it cleanly first
+	if d.Get("force_shutdown").(bool) {
+		if err := powerOffBigvVM(d, bigvClient); err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithTimeout(bigvClient.stopContext(), d.Timeout(schema.TimeoutDelete))
+		defer cancel()
+
+		if err := waitForBigvState(ctx, d, bigvClient, waitForHalted, bigvClient.provisionPollInterval); err != nil {
+			return err
+		}
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s",
+		bigvClient.apiUri(),
 		bigvClient.account,
 		d.Get("group"),
 		d.Id(),
 	)
+	if mode == "purge" {
+		url += "?purge=true"
+	}
+
 	log.Printf("[DEBUG] Deleting VM at %s", url)
 	req, err := http.NewRequest("DELETE", url, nil)
 	if err != nil {
 		return err
 	}
 
-	if resp, err := bigvClient.do(req); err != nil {
+	resp, err := vmDo(d, bigvClient, req)
+
+	// Errors come back with the response, and a VM that's already gone is all we wanted
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		log.Printf("[WARN] VM %s was already deleted from bigv", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	if err != nil {
 		return err
-	} else {
-		// Always close the body when done
-		defer resp.Body.Close()
+	}
 
-		log.Printf("[DEBUG] Delete %s HTTP response Status: %s", d.Id(), resp.Status)
-		if resp.StatusCode != http.StatusNoContent {
-			return fmt.Errorf("Delete VM %s Bad HTTP status from bigv: %d", d.Id(), resp.StatusCode)
-		}
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] Delete %s HTTP response Status: %s", d.Id(), resp.Status)
+	if resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("Delete VM %s Bad HTTP status from bigv: %d", d.Id(), resp.StatusCode)
+	}
+
+	return nil
+}
+
+// powerOffBigvVM
+// Turns the VM off and leaves it off, rather than deleting it
+func powerOffBigvVM(d *schema.ResourceData, bigvClient *client) error {
+	body, err := json.Marshal(bigvVm{
+		Power:  false,
+		Reboot: false,
+	})
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/accounts/%s/groups/%s/virtual_machines/%s",
+		bigvClient.apiUri(),
+		bigvClient.account,
+		d.Get("group"),
+		d.Id(),
+	)
+
+	log.Printf("[DEBUG] Powering off VM at %s", url)
+	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := vmDo(d, bigvClient, req)
+	if err != nil {
+		return err
+	}
+
+	// Always close the body when done
+	defer resp.Body.Close()
+
+	log.Printf("[DEBUG] Power off %s HTTP response Status: %s", d.Id(), resp.Status)
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Power off VM %s Bad HTTP status from bigv: %d", d.Id(), resp.StatusCode)
 	}
 
 	return nil
@@ -557,23 +1581,30 @@ func resourceBigvVMExists(d *schema.ResourceData, meta interface{}) (bool, error
 	bigvClient := meta.(*client)
 
 	url := fmt.Sprintf("%s/virtual_machines/%s",
-		bigvUri,
+		bigvClient.apiUri(),
 		d.Id(),
 	)
 
 	log.Printf("[DEBUG] Checking VM existance at %s", url)
 
 	req, _ := http.NewRequest("GET", url, nil)
-	resp, err := bigvClient.do(req)
+	resp, err := vmDo(d, bigvClient, req)
+
+	// Errors come back with the response, and a VM deleted outside terraform needs recreating rather than failing the plan
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		log.Printf("[WARN] VM %s not found in bigv, removing from state", d.Id())
+		return false, nil
+	}
 	if err != nil {
 		return false, err
 	}
 
+	// Always close the body when done
+	defer resp.Body.Close()
+
 	log.Printf("[DEBUG] Exists %s HTTP response Status: %s", d.Id(), resp.Status)
 	if resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusAccepted {
 		return true, nil
-	} else if resp.StatusCode == http.StatusNotFound {
-		return false, nil
 	}
 
 	return false, fmt.Errorf("Unexpected HTTP status from VM exists check: %d", resp.StatusCode)
@@ -590,16 +1621,19 @@ func resourceFromJson(d *schema.ResourceData, vmJson []byte) error {
 
 	d.SetId(strconv.Itoa(vm.Id))
 	d.Set("name", vm.Name)
+	d.Set("hostname", vm.Hostname)
 	d.Set("cores", vm.Cores)
 	d.Set("memory", vm.Memory)
 	d.Set("power_on", vm.Power)
 	d.Set("reboot", vm.Reboot)
 	d.Set("group_id", vm.GroupId)
 	d.Set("zone", vm.Zone)
+	d.Set("state", vm.state())
 
 	// If we don't get discs back, this was probably an update request
-	if len(vm.Discs) == 1 {
-		d.Set("disk_size", vm.Discs[0].Size)
+	if len(vm.Discs) > 0 {
+		d.Set("disc", discsToResource(d, vm.Discs))
+		d.Set("all_discs", discsList(vm.Discs))
 	}
 
 	// Distribution is empty in create response, leave it with what we sent in
@@ -607,22 +1641,274 @@ func resourceFromJson(d *schema.ResourceData, vmJson []byte) error {
 		d.Set("os", vm.Distribution)
 	}
 
+	if vm.KernelCmdline != nil {
+		d.Set("kernel_cmdline", *vm.KernelCmdline)
+	}
+
+	// Likewise the imager
+	if vm.Imager != "" {
+		d.Set("image_installer", vm.Imager)
+	}
+
 	// Not finding the ips is fine, because they're not sent back in the create request
 	if len(vm.Nics) > 0 {
-		// This is fairly^Wvery^Wacceptably hacky
-		d.Set("ipv4", vm.Nics[0].Ips[0])
-		d.Set("ipv6", vm.Nics[0].Ips[1])
-
-		d.SetConnInfo(map[string]string{
-			"type":     "ssh",
-			"host":     vm.Nics[0].Ips[0],
-			"password": d.Get("root_password").(string),
-		})
+		ipv4, ipv6 := vm.Nics[0].splitIps()
+		d.Set("ipv4", ipv4)
+		d.Set("ipv6", ipv6)
+		d.Set("mac_address", vm.Nics[0].Mac)
+
+		d.SetConnInfo(vmConnInfo(d, ipv4))
+
+		// Only the ones asked for. Nics attached some other way, e.g. by bigv_nic, mustn't recreate the VM
+		if configured := d.Get("additional_nics").([]interface{}); len(configured) > 0 {
+			matched := matchNics(configured, vm.Nics[1:])
+			additionalNics := make([]map[string]interface{}, len(matched))
+			for i, nic := range matched {
+				additionalNics[i] = map[string]interface{}{
+					"label":    nic.Label,
+					"vlan_num": nic.VlanNum,
+				}
+			}
+			d.Set("additional_nics", additionalNics)
+		}
+		d.Set("network_interface", nicsToResource(d, vm.Nics))
+		d.Set("all_network_interfaces", nicsList(vm.Nics))
 	}
 
 	return nil
 }
 
+// state
+// What the VM is up to, as far as a 200 response can tell us
+func (vm *bigvServer) state() string {
+	switch {
+	case vm.Deleted:
+		return "deleted"
+	case vm.Power:
+		return "running"
+	default:
+		return "halted"
+	}
+}
+
+// nicsToResource
+// Keeps the nics in the order we already have them, matching by label,
+// or by position for unlabelled ones. Any we don't know about are left to all_network_interfaces,
+// since bigv_nic attaching one mustn't recreate the VM. Until we have some, e.g. on import, it's all of them.
+func nicsToResource(d *schema.ResourceData, nics []bigvNic) []interface{} {
+	known := d.Get("network_interface").([]interface{})
+	if len(known) == 0 {
+		return nicsList(nics)
+	}
+
+	return nicsList(matchNics(known, nics))
+}
+
+// nicsList
+// The nics as terraform lists them
+func nicsList(nics []bigvNic) []interface{} {
+	list := make([]interface{}, len(nics))
+	for i, nic := range nics {
+		ipv4, ipv6 := nic.splitIps()
+		list[i] = map[string]interface{}{
+			"label":    nic.Label,
+			"vlan_num": nic.VlanNum,
+			"ipv4":     ipv4,
+			"ipv6":     ipv6,
+			"mac":      nic.Mac,
+		}
+	}
+
+	return list
+}
+
+// matchNics
+// The nics for each configured one in turn, matching by label, or by position for unlabelled ones.
+// Configured ones bigv doesn't have are left out, as are any of bigv's nobody asked for.
+func matchNics(configured []interface{}, nics []bigvNic) []bigvNic {
+	var matched []bigvNic
+	used := make([]bool, len(nics))
+
+	for i, v := range configured {
+		label := v.(map[string]interface{})["label"].(string)
+
+		match := -1
+		for j, nic := range nics {
+			if !used[j] && label != "" && nic.Label == label {
+				match = j
+				break
+			}
+		}
+		if match == -1 && i < len(nics) && !used[i] {
+			match = i
+		}
+
+		if match != -1 {
+			used[match] = true
+			matched = append(matched, nics[match])
+		}
+	}
+
+	return matched
+}
+
+// splitIps
+// The first of each ip version on the nic.
+// Don't rely on the order bigv gives them, or on there being both.
+func (n bigvNic) splitIps() (ipv4, ipv6 string) {
+	for _, ip := range n.Ips {
+		parsed := net.ParseIP(ip)
+		switch {
+		case parsed == nil:
+			log.Printf("[WARN] Ignoring unparseable ip from bigv: %s", ip)
+		case parsed.To4() != nil:
+			if ipv4 == "" {
+				ipv4 = ip
+			}
+		default:
+			if ipv6 == "" {
+				ipv6 = ip
+			}
+		}
+	}
+
+	return
+}
+
+// vmConnInfo
+// Connection information for provisioners.
+// VMs that only take keys need the private key from the connection block
+func vmConnInfo(d *schema.ResourceData, host string) map[string]string {
+	connInfo := map[string]string{
+		"type": "ssh",
+	}
+
+	if host != "" {
+		connInfo["host"] = host
+	}
+
+	if !keyAuthOnly(d) {
+		// The bigv_vm data source never has a password
+		password, _ := d.Get("root_password").(string)
+		connInfo["password"] = password
+	}
+
+	return connInfo
+}
+
+// keyAuthOnly
+// Whether the VM will refuse root password logins
+// The bigv_vm data source has neither attribute, so it never is there
+func keyAuthOnly(d *schema.ResourceData) bool {
+	configDrive, _ := d.Get("config_drive").(bool)
+	keyOnly, _ := d.Get("public_key_auth_only").(bool)
+	return configDrive || keyOnly
+}
+
+// sshPublicKeys
+// bigv takes any number of keys, one per line.
+// Named keys are looked up in the account, since the image only takes key material.
+func sshPublicKeys(d *schema.ResourceData, bigvClient *client) (string, error) {
+	var keys []string
+	for _, key := range d.Get("ssh_public_key").([]interface{}) {
+		keys = append(keys, strings.TrimSpace(key.(string)))
+	}
+
+	var names []string
+	for _, name := range d.Get("ssh_key_names").([]interface{}) {
+		names = append(names, name.(string))
+	}
+
+	if len(names) > 0 {
+		named, err := readBigvSshKeysByName(bigvClient, names)
+		if err != nil {
+			return "", err
+		}
+		for _, key := range named {
+			keys = append(keys, strings.TrimSpace(key))
+		}
+	}
+
+	return strings.Join(keys, "\n"), nil
+}
+
+// discsFromResource
+// The discs to create the VM with, a single default one if none are given
+func discsFromResource(d *schema.ResourceData, bigvClient *client) []bigvDisc {
+	list := d.Get("disc").([]interface{})
+	if len(list) == 0 {
+		return []bigvDisc{{
+			Label:        "root",
+			StorageGrade: bigvClient.defaultStorageGrade,
+			Size:         bigvClient.defaultDiscSize,
+		}}
+	}
+
+	discs := make([]bigvDisc, len(list))
+	for i, v := range list {
+		disc := v.(map[string]interface{})
+		discs[i] = bigvDisc{
+			Label:        disc["label"].(string),
+			StorageGrade: disc["storage_grade"].(string),
+			Size:         disc["size"].(int),
+		}
+
+		// Unset ones come from the provider's defaults
+		if discs[i].StorageGrade == "" {
+			discs[i].StorageGrade = bigvClient.defaultStorageGrade
+		}
+		if discs[i].Size == 0 {
+			discs[i].Size = bigvClient.defaultDiscSize
+		}
+	}
+
+	return discs
+}
+
+// discsToResource
+// bigv doesn't promise to list discs in the order they were created,
+// so they're matched up with what we already have by label to avoid spurious diffs.
+// Any we don't know about are left to all_discs, since bigv_disc adding one mustn't recreate the VM.
+// Until we have some, e.g. on import, it's all of them.
+func discsToResource(d *schema.ResourceData, discs []bigvDisc) []interface{} {
+	known := d.Get("disc").([]interface{})
+	if len(known) == 0 {
+		return discsList(discs)
+	}
+
+	byLabel := make(map[string]bigvDisc, len(discs))
+	for _, disc := range discs {
+		byLabel[disc.Label] = disc
+	}
+
+	matched := make([]bigvDisc, 0, len(known))
+	for _, v := range known {
+		label := v.(map[string]interface{})["label"].(string)
+		if disc, ok := byLabel[label]; ok {
+			matched = append(matched, disc)
+			delete(byLabel, label)
+		}
+	}
+
+	return discsList(matched)
+}
+
+// discsList
+// The discs as terraform lists them
+func discsList(discs []bigvDisc) []interface{} {
+	list := make([]interface{}, len(discs))
+	for i, disc := range discs {
+		list[i] = map[string]interface{}{
+			"label":            disc.Label,
+			"size":             disc.Size,
+			"storage_grade":    disc.StorageGrade,
+			"provisioned_iops": disc.ProvisionedIops,
+		}
+	}
+
+	return list
+}
+
 /* computeCoresToMemory
 bigv charges per 1GiB memory, and you automatically get 1 more core per 4GiB.
 See: http://www.bigv.io/prices
@@ -636,13 +1922,13 @@ func (v *bigvVm) computeCoresToMemory() error {
 		v.Memory = 1024
 	case v.Cores == 0:
 		// Just the cores calculated
-		v.Cores = int(math.Ceil(float64(v.Memory/4096) + float64(0.01)))
+		v.Cores = coresForMemory(v.Memory)
 	case v.Memory == 0:
 		// Just the memory calculated
-		v.Memory = int(math.Max(1024, float64((v.Cores-1)*4096)))
+		v.Memory = memoryForCores(v.Cores)
 	default:
 		// Both set, so validate them
-		expectedCores := int(math.Ceil(float64(v.Memory/4096) + float64(0.01)))
+		expectedCores := coresForMemory(v.Memory)
 		if expectedCores != v.Cores {
 			return fmt.Errorf("Memory and cores mismatch!\nExpected %d cores for your %dGiB memory, but you have %d.\nSpecify 1 cores per 4GiB memory.\nSee: http://www.bigv.io/prices", expectedCores, v.Memory/1024, v.Cores)
 		}
@@ -651,14 +1937,57 @@ func (v *bigvVm) computeCoresToMemory() error {
 	return nil
 }
 
+// validateKernelCmdline
+// Nothing a shell would do anything with
+func validateKernelCmdline(v interface{}, k string) (ws []string, es []error) {
+	if i := strings.IndexAny(v.(string), ";&|$`<>()\\'\"\n"); i != -1 {
+		es = append(es, fmt.Errorf("%s must not contain shell characters, found: %q", k, v.(string)[i]))
+	}
+	return
+}
+
+// coresForMemory
+// 1 core per 4GiB, or part of one. So 1-4GiB is 1 core, 5-8GiB 2 cores, 9GiB 3 cores.
+func coresForMemory(memory int) int {
+	return int(math.Ceil(float64(memory) / 4096))
+}
+
+// memoryForCores
+// The least memory coresForMemory gives that many cores for
+func memoryForCores(cores int) int {
+	if cores <= 1 {
+		return 1024
+	}
+
+	return (cores-1)*4096 + 1024
+}
+
 const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
 
-func randomPassword() string {
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+// randomPassword
+// Root passwords need a cryptographic source.
+// Bytes that don't divide evenly into letters are thrown away, so every letter is equally likely.
+func randomPassword() (string, error) {
+	// The largest multiple of len(letters) a byte can hold
+	limit := 256 - 256%len(letters)
 
 	b := make([]byte, passwordLength)
-	for i := range b {
-		b[i] = letters[r.Intn(len(letters))]
+	random := make([]byte, passwordLength)
+	for i := 0; i < len(b); {
+		if _, err := rand.Read(random); err != nil {
+			return "", fmt.Errorf("Error generating root password: %s", err)
+		}
+
+		for _, r := range random {
+			if int(r) >= limit {
+				continue
+			}
+			b[i] = letters[int(r)%len(letters)]
+			i++
+			if i == len(b) {
+				break
+			}
+		}
 	}
-	return string(b)
+	return string(b), nil
 }
@@ -0,0 +1,120 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func resourceBigvVMMigrateState(v int, is *terraform.InstanceState, meta interface{}) (*terraform.InstanceState, error) {
+	switch v {
+	case 0:
+		log.Println("[INFO] Found bigv VM State v0; migrating to v1")
+		var err error
+		is, err = migrateBigvVMStateV0toV1(is)
+		if err != nil {
+			return is, err
+		}
+		fallthrough
+	case 1:
+		log.Println("[INFO] Found bigv VM State v1; migrating to v2")
+		var err error
+		is, err = migrateBigvVMStateV1toV2(is)
+		if err != nil {
+			return is, err
+		}
+		fallthrough
+	case 2:
+		log.Println("[INFO] Found bigv VM State v2; migrating to v3")
+		return migrateBigvVMStateV2toV3(is)
+	default:
+		return is, fmt.Errorf("Unexpected schema version: %d", v)
+	}
+}
+
+// migrateBigvVMStateV0toV1
+// VMs from before locale existed have the default locale,
+// which stops the new ForceNew attribute recreating them
+func migrateBigvVMStateV0toV1(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] Empty bigv VM State; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] Attributes before migration: %#v", is.Attributes)
+
+	if _, ok := is.Attributes["locale"]; !ok {
+		is.Attributes["locale"] = defaultLocale
+	}
+
+	log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
+	return is, nil
+}
+
+// migrateBigvVMStateV1toV2
+// The flat disc attributes became the first of the disc blocks
+func migrateBigvVMStateV1toV2(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] Empty bigv VM State; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] Attributes before migration: %#v", is.Attributes)
+
+	size, ok := is.Attributes["disc_size"]
+	if !ok {
+		size = strconv.Itoa(defaultDiscSize)
+	}
+	grade, ok := is.Attributes["storage_grade"]
+	if !ok {
+		grade = defaultStorageGrade
+	}
+	iops, ok := is.Attributes["provisioned_iops"]
+	if !ok {
+		iops = "0"
+	}
+
+	is.Attributes["disc.#"] = "1"
+	is.Attributes["disc.0.label"] = "root"
+	is.Attributes["disc.0.size"] = size
+	is.Attributes["disc.0.storage_grade"] = grade
+	is.Attributes["disc.0.provisioned_iops"] = iops
+
+	delete(is.Attributes, "disc_size")
+	delete(is.Attributes, "storage_grade")
+	delete(is.Attributes, "provisioned_iops")
+
+	log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
+	return is, nil
+}
+
+// migrateBigvVMStateV2toV3
+// ssh_public_key became a list, where it used to be newline separated keys
+func migrateBigvVMStateV2toV3(is *terraform.InstanceState) (*terraform.InstanceState, error) {
+	if is.Empty() || is.Attributes == nil {
+		log.Println("[DEBUG] Empty bigv VM State; nothing to migrate.")
+		return is, nil
+	}
+
+	log.Printf("[DEBUG] Attributes before migration: %#v", is.Attributes)
+
+	keys, ok := is.Attributes["ssh_public_key"]
+	if ok {
+		delete(is.Attributes, "ssh_public_key")
+
+		n := 0
+		for _, key := range strings.Split(keys, "\n") {
+			if key = strings.TrimSpace(key); key != "" {
+				is.Attributes[fmt.Sprintf("ssh_public_key.%d", n)] = key
+				n++
+			}
+		}
+		is.Attributes["ssh_public_key.#"] = strconv.Itoa(n)
+	}
+
+	log.Printf("[DEBUG] Attributes after migration: %#v", is.Attributes)
+	return is, nil
+}
@@ -0,0 +1,152 @@
+package bigv
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestMigrateBigvVMStateV0toV1(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"name": "web",
+		},
+	}
+
+	is, err := resourceBigvVMMigrateState(0, is, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is.Attributes["locale"] != defaultLocale {
+		t.Fatalf("expected locale %s, got %q", defaultLocale, is.Attributes["locale"])
+	}
+}
+
+func TestMigrateBigvVMStateV0toV1KeepsLocale(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"name":   "web",
+			"locale": "en_US.UTF-8",
+		},
+	}
+
+	is, err := migrateBigvVMStateV0toV1(is)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is.Attributes["locale"] != "en_US.UTF-8" {
+		t.Fatalf("expected locale en_US.UTF-8 to be kept, got %q", is.Attributes["locale"])
+	}
+}
+
+func TestMigrateBigvVMStateEmpty(t *testing.T) {
+	is, err := resourceBigvVMMigrateState(0, &terraform.InstanceState{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !is.Empty() {
+		t.Fatalf("expected an empty state, got %#v", is)
+	}
+}
+
+func TestMigrateBigvVMStateV1toV2(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"name":             "web",
+			"locale":           defaultLocale,
+			"disc_size":        "51200",
+			"storage_grade":    "ssd",
+			"provisioned_iops": "3000",
+		},
+	}
+
+	is, err := resourceBigvVMMigrateState(1, is, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"disc.#":                  "1",
+		"disc.0.label":            "root",
+		"disc.0.size":             "51200",
+		"disc.0.storage_grade":    "ssd",
+		"disc.0.provisioned_iops": "3000",
+	}
+	for k, v := range expected {
+		if is.Attributes[k] != v {
+			t.Errorf("%s: expected %s, got %q", k, v, is.Attributes[k])
+		}
+	}
+
+	for _, k := range []string{"disc_size", "storage_grade", "provisioned_iops"} {
+		if _, ok := is.Attributes[k]; ok {
+			t.Errorf("expected %s to be removed", k)
+		}
+	}
+}
+
+func TestMigrateBigvVMStateV1toV2Defaults(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID:         "1",
+		Attributes: map[string]string{"name": "web"},
+	}
+
+	is, err := migrateBigvVMStateV1toV2(is)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if is.Attributes["disc.0.size"] != "25600" || is.Attributes["disc.0.storage_grade"] != defaultStorageGrade {
+		t.Fatalf("expected the default disc, got %#v", is.Attributes)
+	}
+}
+
+func TestMigrateBigvVMStateV2toV3(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"name":           "web",
+			"ssh_public_key": "ssh-ed25519 AAAA alice\n\nssh-rsa BBBB bob\n",
+		},
+	}
+
+	is, err := resourceBigvVMMigrateState(2, is, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"ssh_public_key.#": "2",
+		"ssh_public_key.0": "ssh-ed25519 AAAA alice",
+		"ssh_public_key.1": "ssh-rsa BBBB bob",
+	}
+	for k, v := range expected {
+		if is.Attributes[k] != v {
+			t.Errorf("%s: expected %q, got %q", k, v, is.Attributes[k])
+		}
+	}
+	if _, ok := is.Attributes["ssh_public_key"]; ok {
+		t.Error("expected the old string to be removed")
+	}
+}
+
+func TestMigrateBigvVMStateV2toV3WithoutKeys(t *testing.T) {
+	is := &terraform.InstanceState{
+		ID:         "1",
+		Attributes: map[string]string{"name": "web"},
+	}
+
+	is, err := migrateBigvVMStateV2toV3(is)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := is.Attributes["ssh_public_key.#"]; ok {
+		t.Fatalf("expected no keys, got %#v", is.Attributes)
+	}
+}
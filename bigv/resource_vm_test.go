@@ -0,0 +1,1333 @@
+package bigv
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// testVMResourceData
+// A VM that's been created already, with the given config
+func testVMResourceData(t *testing.T, config map[string]interface{}) *schema.ResourceData {
+	if _, ok := config["name"]; !ok {
+		config["name"] = "web"
+	}
+
+	d := schema.TestResourceDataRaw(t, resourceBigvVM().Schema, config)
+	d.SetId("1")
+
+	return d
+}
+
+// waitForGoroutines
+// Fails unless the goroutines get back down to before, giving them a moment to finish
+func waitForGoroutines(t *testing.T, before int) {
+	for i := 0; i < 100; i++ {
+		if runtime.NumGoroutine() <= before {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("expected %d goroutines once the wait was done, got %d", before, runtime.NumGoroutine())
+}
+
+func TestWaitForBigvStateLeavesNothingRunning(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	polls := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		polls++
+		if polls < 3 {
+			w.WriteHeader(http.StatusAccepted)
+			w.Write([]byte(`{"id":1,"name":"web","power_on":false}`))
+			return
+		}
+		w.Write([]byte(`{"id":1,"name":"web","power_on":true}`))
+	})
+
+	d := testVMResourceData(t, map[string]interface{}{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := waitForBigvState(ctx, d, c, waitForPowered, 10*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if polls != 3 {
+		t.Fatalf("expected 3 polls, got %d", polls)
+	}
+
+	server.Close()
+	http.DefaultTransport.(*http.Transport).CloseIdleConnections()
+	waitForGoroutines(t, before)
+}
+
+func TestWaitForVmSshTimesOut(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	// Either nothing listens on port 22 locally, or it won't take the empty root password
+	d := testVMResourceData(t, map[string]interface{}{
+		"ipv4": "127.0.0.1",
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if err := waitForVmSsh(ctx, d, 10*time.Millisecond); err == nil {
+		t.Fatal("expected the wait to time out")
+	}
+
+	waitForGoroutines(t, before)
+}
+
+func TestResourceFromJson(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{})
+
+	vm := []byte(`{
+		"id": 1,
+		"name": "web",
+		"hostname": "web.default.myaccount.uk0.bigv.io",
+		"cores": 2,
+		"memory": 4096,
+		"last_imaged_with": "stretch",
+		"last_imager": "bigv-imager-2.1",
+		"power_on": true,
+		"autoreboot_on": true,
+		"group_id": 12,
+		"zone_name": "manchester",
+		"discs": [{"id": 5, "label": "root", "storage_grade": "ssd", "size": 51200, "provisioned_iops": 3000}],
+		"network_interfaces": [{"id": 7, "label": "", "ips": ["192.0.2.10", "2001:db8::10"], "mac": "fe:ff:00:00:00:01"}]
+	}`)
+
+	if err := resourceFromJson(d, vm); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":                    "web",
+		"hostname":                "web.default.myaccount.uk0.bigv.io",
+		"cores":                   2,
+		"memory":                  4096,
+		"os":                      "stretch",
+		"image_installer":         "bigv-imager-2.1",
+		"power_on":                true,
+		"reboot":                  true,
+		"group_id":                12,
+		"zone":                    "manchester",
+		"state":                   "running",
+		"disc.#":                  1,
+		"disc.0.label":            "root",
+		"disc.0.size":             51200,
+		"disc.0.storage_grade":    "ssd",
+		"disc.0.provisioned_iops": 3000,
+		"ipv4":                    "192.0.2.10",
+		"ipv6":                    "2001:db8::10",
+		"mac_address":             "fe:ff:00:00:00:01",
+	}
+	for k, v := range expected {
+		if got := d.Get(k); got != v {
+			t.Errorf("%s: expected %v, got %v", k, v, got)
+		}
+	}
+	if d.Id() != "1" {
+		t.Errorf("expected id 1, got %s", d.Id())
+	}
+}
+
+func TestCoresForMemory(t *testing.T) {
+	cases := []struct {
+		memory, cores int
+	}{
+		{1024, 1},
+		{3072, 1},
+		{4095, 1},
+		{4096, 1},
+		{4097, 2},
+		{5120, 2},
+		{8191, 2},
+		{8192, 2},
+		{8193, 3},
+		{9216, 3},
+		{12288, 3},
+		{12289, 4},
+		{16384, 4},
+		{16385, 5},
+	}
+
+	for _, c := range cases {
+		if cores := coresForMemory(c.memory); cores != c.cores {
+			t.Errorf("%dMiB: expected %d cores, got %d", c.memory, c.cores, cores)
+		}
+	}
+}
+
+func TestMemoryForCores(t *testing.T) {
+	cases := []struct {
+		cores, memory int
+	}{
+		{0, 1024},
+		{1, 1024},
+		{2, 5120},
+		{3, 9216},
+		{4, 13312},
+	}
+
+	for _, c := range cases {
+		if memory := memoryForCores(c.cores); memory != c.memory {
+			t.Errorf("%d cores: expected %dMiB, got %d", c.cores, c.memory, memory)
+		}
+	}
+
+	// 1GiB less would be a core fewer
+	for cores := 2; cores <= 8; cores++ {
+		memory := memoryForCores(cores)
+		if coresForMemory(memory) != cores || coresForMemory(memory-1024) != cores-1 {
+			t.Errorf("%d cores: %dMiB isn't the least memory for them", cores, memory)
+		}
+	}
+}
+
+func TestComputeCoresToMemory(t *testing.T) {
+	for cores := 1; cores <= 8; cores++ {
+		vm := bigvVm{Cores: cores}
+		if err := vm.computeCoresToMemory(); err != nil {
+			t.Fatal(err)
+		}
+
+		// The memory given for the cores has to pass validation itself
+		if err := (&bigvVm{Cores: cores, Memory: vm.Memory}).computeCoresToMemory(); err != nil {
+			t.Errorf("%d cores: %s", cores, err)
+		}
+	}
+
+	if err := (&bigvVm{Cores: 3, Memory: 8192}).computeCoresToMemory(); err == nil {
+		t.Error("expected 3 cores for 8GiB to be rejected")
+	}
+}
+
+func TestRandomPassword(t *testing.T) {
+	seen := map[string]bool{}
+	counts := map[rune]int{}
+
+	for i := 0; i < 1000; i++ {
+		password, err := randomPassword()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(password) != passwordLength {
+			t.Fatalf("expected %d characters, got %d", passwordLength, len(password))
+		}
+		if seen[password] {
+			t.Fatalf("got the same password twice: %s", password)
+		}
+		seen[password] = true
+
+		for _, r := range password {
+			if !strings.ContainsRune(letters, r) {
+				t.Fatalf("unexpected character %q in %s", r, password)
+			}
+			counts[r]++
+		}
+	}
+
+	// 48000 characters over 62 letters is about 774 each. Any bias shows up well outside this
+	for _, r := range letters {
+		if counts[r] < 600 || counts[r] > 950 {
+			t.Errorf("%q came up %d times, expected about 774", r, counts[r])
+		}
+	}
+}
+
+// testBigvVMServer
+// A fake bigv with VM 1 in it, answering everything a VM refresh or update asks for
+type testBigvVMServer struct {
+	mu   sync.Mutex
+	vm   bigvServer
+	gone bool
+	puts []bigvVm
+
+	// Reads left before a restarting VM is powered again
+	restarting int
+}
+
+func newTestBigvVMServer() *testBigvVMServer {
+	return &testBigvVMServer{
+		vm: bigvServer{
+			bigvVm: bigvVm{
+				Id:           1,
+				Name:         "web",
+				Hostname:     "web.default.myaccount.uk0.bigv.io",
+				Cores:        1,
+				Memory:       1024,
+				Distribution: "bookworm",
+				Imager:       "bigv-imager-2.1",
+				Power:        true,
+				Reboot:       true,
+				GroupId:      12,
+				Zone:         "york",
+			},
+			Discs: []bigvDisc{{Id: 5, Label: "root", StorageGrade: "sata", Size: 25600}},
+			Nics:  []bigvNic{{Id: 7, Ips: []string{"192.0.2.10", "2001:db8::10"}, Mac: "fe:ff:00:00:00:01"}},
+		},
+	}
+}
+
+func (s *testBigvVMServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case s.gone:
+		w.WriteHeader(http.StatusNotFound)
+	case r.URL.Path == "/virtual_machines/1":
+		if s.restarting > 0 {
+			s.restarting--
+			s.vm.Power = s.restarting == 0
+		}
+		json.NewEncoder(w).Encode(s.vm)
+	case r.Method == "PUT" && r.URL.Path == "/accounts/myaccount/groups/default/virtual_machines/1":
+		vm := bigvVm{}
+		json.NewDecoder(r.Body).Decode(&vm)
+		s.puts = append(s.puts, vm)
+
+		if vm.Cores != 0 {
+			s.vm.Cores = vm.Cores
+			s.vm.Memory = vm.Memory
+		}
+		if vm.KernelCmdline != nil {
+			s.vm.KernelCmdline = vm.KernelCmdline
+		}
+		// Powering off with reboot on comes back up, after a while
+		s.vm.Power = vm.Power
+		s.vm.Reboot = vm.Reboot
+		if !vm.Power && vm.Reboot {
+			s.restarting = 2
+		}
+
+		json.NewEncoder(w).Encode(s.vm.bigvVm)
+	case r.URL.Path == "/accounts/myaccount/groups/12":
+		w.Write([]byte(`{"id":12,"name":"default","billing_code":"ops-123"}`))
+	case r.URL.Path == "/virtual_machines/1/security_groups":
+		w.Write([]byte(`{"security_group_ids":[]}`))
+	case r.URL.Path == "/ips/192.0.2.10":
+		w.Write([]byte(`{"ip":"192.0.2.10","rdns":"web.default.myaccount.uk0.bigv.io"}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// testVMRefresh
+// What terraform refresh makes of the VM, starting from nothing but its id
+func testVMRefresh(t *testing.T, c *client, state *terraform.InstanceState) *terraform.InstanceState {
+	if state == nil {
+		state = &terraform.InstanceState{
+			ID: "1",
+			// What create leaves behind that only terraform knows
+			Attributes: map[string]string{
+				"name":                 "web",
+				"locale":               "en_GB.UTF-8",
+				"config_drive":         "false",
+				"public_key_auth_only": "false",
+				"destroy_mode":         "purge",
+				"purge":                "true",
+				"force_shutdown":       "false",
+				"provisioning_timeout": "1200",
+				"api_timeout_override": "0",
+			},
+			Meta: map[string]interface{}{"schema_version": "3"},
+		}
+	}
+
+	state, err := resourceBigvVM().Refresh(state, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return state
+}
+
+// testVMPlan
+// What terraform plan shows for the config, given the state
+func testVMPlan(t *testing.T, c *client, state *terraform.InstanceState, raw map[string]interface{}) *terraform.InstanceDiff {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := resourceBigvVM().Diff(state, terraform.NewResourceConfig(rc), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return diff
+}
+
+func TestVMDriftShowsInPlan(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	config := map[string]interface{}{"name": "web", "cores": 1}
+
+	state := testVMRefresh(t, c, nil)
+	if diff := testVMPlan(t, c, state, config); diff != nil && diff.Attributes["cores"] != nil {
+		t.Fatalf("expected no change to cores before the drift, got %#v", diff.Attributes["cores"])
+	}
+
+	// Resized in the control panel
+	fake.vm.Cores = 2
+	fake.vm.Memory = 5120
+
+	state = testVMRefresh(t, c, state)
+	if state.Attributes["cores"] != "2" || state.Attributes["memory"] != "5120" {
+		t.Fatalf("expected refresh to pick up 2 cores and 5120MiB, got %s and %s", state.Attributes["cores"], state.Attributes["memory"])
+	}
+
+	diff := testVMPlan(t, c, state, config)
+	if diff == nil || diff.Attributes["cores"] == nil {
+		t.Fatal("expected the plan to put cores back")
+	}
+	if cores := diff.Attributes["cores"]; cores.Old != "2" || cores.New != "1" {
+		t.Fatalf("expected cores to go from 2 to 1, got %s to %s", cores.Old, cores.New)
+	}
+}
+
+func TestVMDeletedOutsideTerraform(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	state := testVMRefresh(t, c, nil)
+
+	fake.gone = true
+
+	// Removing it from state lets the next plan create it again
+	if state = testVMRefresh(t, c, state); state != nil {
+		t.Fatalf("expected the VM to be removed from state, got %#v", state)
+	}
+}
+
+func TestVMRebootOnlyUpdate(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	state := testVMRefresh(t, c, nil)
+
+	diff := testVMPlan(t, c, state, map[string]interface{}{"name": "web", "reboot": false})
+	if diff == nil || diff.Attributes["reboot"] == nil {
+		t.Fatal("expected the plan to change reboot")
+	}
+
+	state, err := resourceBigvVM().Apply(state, diff, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.puts) != 1 {
+		t.Fatalf("expected the change to be sent to bigv, got %d updates", len(fake.puts))
+	}
+	if put := fake.puts[0]; put.Reboot || !put.Power {
+		t.Fatalf("expected reboot off with the power left on, got %#v", put)
+	}
+	if state.Attributes["reboot"] != "false" {
+		t.Fatalf("expected reboot false in state, got %s", state.Attributes["reboot"])
+	}
+}
+
+func TestDiscsFromResource(t *testing.T) {
+	c := &client{defaultDiscSize: 25600, defaultStorageGrade: "sata"}
+
+	d := testVMResourceData(t, map[string]interface{}{
+		"disc": []interface{}{
+			map[string]interface{}{"label": "root", "size": 25600, "storage_grade": "ssd"},
+			map[string]interface{}{"label": "data", "size": 102400},
+			map[string]interface{}{"label": "archive", "size": 512000, "storage_grade": "archive"},
+		},
+	})
+
+	expected := []bigvDisc{
+		{Label: "root", Size: 25600, StorageGrade: "ssd"},
+		{Label: "data", Size: 102400, StorageGrade: "sata"},
+		{Label: "archive", Size: 512000, StorageGrade: "archive"},
+	}
+
+	discs := discsFromResource(d, c)
+	if len(discs) != len(expected) {
+		t.Fatalf("expected %d discs, got %d", len(expected), len(discs))
+	}
+	for i := range expected {
+		if discs[i] != expected[i] {
+			t.Errorf("disc %d: expected %#v, got %#v", i, expected[i], discs[i])
+		}
+	}
+
+	// Without any, VMs get the provider's default disc
+	discs = discsFromResource(testVMResourceData(t, map[string]interface{}{}), c)
+	if len(discs) != 1 || discs[0] != (bigvDisc{Label: "root", Size: 25600, StorageGrade: "sata"}) {
+		t.Fatalf("expected the default disc, got %#v", discs)
+	}
+}
+
+func TestDiscsToResourceMatchesLabels(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{
+		"disc": []interface{}{
+			map[string]interface{}{"label": "root", "size": 25600},
+			map[string]interface{}{"label": "data", "size": 102400},
+			map[string]interface{}{"label": "archive", "size": 512000},
+		},
+	})
+
+	// bigv lists them in its own order
+	discs := discsToResource(d, []bigvDisc{
+		{Id: 3, Label: "archive", Size: 512000, StorageGrade: "archive"},
+		{Id: 1, Label: "root", Size: 25600, StorageGrade: "sata"},
+		{Id: 2, Label: "data", Size: 102400, StorageGrade: "sata"},
+	})
+
+	for i, label := range []string{"root", "data", "archive"} {
+		if got := discs[i].(map[string]interface{})["label"]; got != label {
+			t.Errorf("disc %d: expected %s, got %s", i, label, got)
+		}
+	}
+}
+
+func TestSshPublicKeysJoined(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{
+		"ssh_public_key": []interface{}{"ssh-ed25519 AAAA alice\n", "  ssh-rsa BBBB bob"},
+	})
+
+	keys, err := sshPublicKeys(d, &client{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys != "ssh-ed25519 AAAA alice\nssh-rsa BBBB bob" {
+		t.Fatalf("expected one key per line, got %q", keys)
+	}
+}
+
+// testVMPlanEmpty
+// Fails with whatever the plan would change
+func testVMPlanEmpty(t *testing.T, diff *terraform.InstanceDiff) {
+	if diff.Empty() {
+		return
+	}
+
+	for k, attr := range diff.Attributes {
+		t.Errorf("%s: %q => %q (forces new resource: %t)", k, attr.Old, attr.New, attr.RequiresNew)
+	}
+	t.Fatal("expected nothing to change after a refresh")
+}
+
+func TestVMAdditionalNicsRefreshPlansNothing(t *testing.T) {
+	fake := newTestBigvVMServer()
+	fake.vm.Nics = append(fake.vm.Nics, bigvNic{Id: 8, Label: "private", VlanNum: 1519, Mac: "fe:ff:00:00:00:02"})
+
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	// Without additional_nics, a second nic is no reason to recreate the VM
+	state := testVMRefresh(t, c, nil)
+	testVMPlanEmpty(t, testVMPlan(t, c, state, map[string]interface{}{"name": "web"}))
+
+	// And with them, as create leaves them, they're matched with bigv's
+	state.Attributes["additional_nics.#"] = "1"
+	state.Attributes["additional_nics.0.label"] = "private"
+	state.Attributes["additional_nics.0.vlan_num"] = "1519"
+
+	config := map[string]interface{}{
+		"name": "web",
+		"additional_nics": []interface{}{
+			map[string]interface{}{"label": "private", "vlan_num": 1519},
+		},
+	}
+
+	// Another attached separately, e.g. by bigv_nic
+	fake.vm.Nics = append(fake.vm.Nics, bigvNic{Id: 9, Label: "backup", VlanNum: 1520, Mac: "fe:ff:00:00:00:03"})
+
+	state = testVMRefresh(t, c, state)
+	testVMPlanEmpty(t, testVMPlan(t, c, state, config))
+}
+
+func TestVMAttachedDiscsAndNicsPlanNothing(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	config := map[string]interface{}{
+		"name": "web",
+		"disc": []interface{}{
+			map[string]interface{}{"label": "root", "size": 25600, "storage_grade": "sata"},
+		},
+		"network_interface": []interface{}{
+			map[string]interface{}{"ipv4": "192.0.2.10"},
+		},
+	}
+
+	state := testVMRefresh(t, c, nil)
+	testVMPlanEmpty(t, testVMPlan(t, c, state, config))
+
+	// Added by bigv_disc and bigv_nic, listed first to be sure they're not matched by position
+	fake.vm.Discs = append([]bigvDisc{{Id: 6, Label: "data", StorageGrade: "ssd", Size: 102400}}, fake.vm.Discs...)
+	fake.vm.Nics = append(fake.vm.Nics, bigvNic{Id: 8, Label: "private", VlanNum: 1519, Mac: "fe:ff:00:00:00:02"})
+
+	state = testVMRefresh(t, c, state)
+	testVMPlanEmpty(t, testVMPlan(t, c, state, config))
+
+	expected := map[string]string{
+		"disc.#":                         "1",
+		"disc.0.label":                   "root",
+		"network_interface.#":            "1",
+		"network_interface.0.ipv4":       "192.0.2.10",
+		"all_discs.#":                    "2",
+		"all_discs.0.label":              "data",
+		"all_discs.1.label":              "root",
+		"all_network_interfaces.#":       "2",
+		"all_network_interfaces.1.label": "private",
+	}
+	for k, v := range expected {
+		if state.Attributes[k] != v {
+			t.Errorf("%s: expected %s, got %q", k, v, state.Attributes[k])
+		}
+	}
+}
+
+func TestDiscsToResourceImportsEveryDisc(t *testing.T) {
+	d := testVMResourceData(t, map[string]interface{}{})
+
+	discs := discsToResource(d, []bigvDisc{
+		{Id: 1, Label: "root", Size: 25600, StorageGrade: "sata"},
+		{Id: 2, Label: "data", Size: 102400, StorageGrade: "sata"},
+	})
+	if len(discs) != 2 {
+		t.Fatalf("expected both discs without any in state, got %d", len(discs))
+	}
+}
+
+func TestReadBigvIPv4PtrNotFound(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	d := testVMResourceData(t, map[string]interface{}{"ipv4": "192.0.2.10"})
+	d.Set("ipv4_ptr", "stale.example.com")
+
+	if err := readBigvIPv4Ptr(d, c); err != nil {
+		t.Fatal(err)
+	}
+	if ptr := d.Get("ipv4_ptr").(string); ptr != "" {
+		t.Fatalf("expected no PTR record, got %s", ptr)
+	}
+}
+
+func TestValidateBigvOsName(t *testing.T) {
+	cases := []struct {
+		os       string
+		warnings int
+		errors   int
+	}{
+		{"bookworm", 0, 0},
+		{"jammy", 0, 0},
+		{"none", 0, 0},
+		{"vivid", 1, 0},
+		{"Stretch", 1, 0},
+		{"CENTOS7", 1, 0},
+		{"windows95", 0, 1},
+	}
+
+	for _, c := range cases {
+		ws, es := validateBigvOsName(c.os, "os")
+		if len(ws) != c.warnings || len(es) != c.errors {
+			t.Errorf("%s: expected %d warnings and %d errors, got %v and %v", c.os, c.warnings, c.errors, ws, es)
+		}
+	}
+}
+
+func TestVMEolOsWarnsInPlan(t *testing.T) {
+	rc, err := config.NewRawConfig(map[string]interface{}{"name": "web", "os": "Vivid"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ws, es := resourceBigvVM().Validate(terraform.NewResourceConfig(rc))
+	if len(es) > 0 {
+		t.Fatal(es)
+	}
+	if len(ws) != 1 || !strings.Contains(ws[0], "vivid has been end of life since 2016-02") {
+		t.Fatalf("expected a warning about vivid, got %v", ws)
+	}
+}
+
+func TestVMGroupChangeForcesNewInPlan(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	state := testVMRefresh(t, c, nil)
+
+	var diff *terraform.InstanceDiff
+	logged := testCaptureLog(func() {
+		diff = testVMPlan(t, c, state, map[string]interface{}{"name": "web", "group": "staging"})
+	})
+	if !strings.Contains(logged, "[WARN] Warning: changing 'group' of VM 1 from default to staging will destroy and recreate the VM") {
+		t.Errorf("expected a warning about replacing the VM, got:\n%s", logged)
+	}
+	if diff == nil || diff.Attributes["group"] == nil {
+		t.Fatal("expected the plan to change group")
+	}
+	if group := diff.Attributes["group"]; group.Old != "default" || group.New != "staging" || !group.RequiresNew {
+		t.Fatalf("expected group default => staging (forces new resource), got %#v", group)
+	}
+	if !diff.RequiresNew() {
+		t.Fatal("expected the VM to be replaced")
+	}
+}
+
+func TestLockCreatePipelineSmart(t *testing.T) {
+	c := &client{createPipelineMode: "smart", creates: make(chan struct{}, 2)}
+
+	var unlocks []func()
+	for i := 0; i < 2; i++ {
+		unlock, err := lockCreatePipeline(context.Background(), c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		unlocks = append(unlocks, unlock)
+	}
+
+	// A third has to wait its turn, so gives up when it runs out of time
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := lockCreatePipeline(ctx, c); err == nil {
+		t.Fatal("expected a third create to time out waiting")
+	}
+
+	unlocks[0]()
+
+	unlock, err := lockCreatePipeline(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	unlocks[1]()
+
+	if len(c.creates) != 0 {
+		t.Fatalf("expected every create to have let go, %d still held", len(c.creates))
+	}
+}
+
+func TestLockCreatePipelineSerial(t *testing.T) {
+	c := &client{createPipelineMode: "serial"}
+
+	unlock, err := lockCreatePipeline(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		next, _ := lockCreatePipeline(context.Background(), c)
+		close(locked)
+		next()
+	}()
+
+	select {
+	case <-locked:
+		t.Fatal("expected the second create to wait for the first")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+}
+
+func TestVMKernelCmdlineUpdateWaitsForRestart(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	c.resizePollInterval = 10 * time.Millisecond
+
+	state := testVMRefresh(t, c, nil)
+
+	diff := testVMPlan(t, c, state, map[string]interface{}{"name": "web", "kernel_cmdline": "mitigations=off"})
+	state, err := resourceBigvVM().Apply(state, diff, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(fake.puts) != 1 {
+		t.Fatalf("expected one update, got %d", len(fake.puts))
+	}
+	if put := fake.puts[0]; put.Power || !put.Reboot || put.KernelCmdline == nil || *put.KernelCmdline != "mitigations=off" {
+		t.Fatalf("expected a restart with the new kernel_cmdline, got %#v", put)
+	}
+
+	if fake.restarting != 0 {
+		t.Fatal("expected the update to wait for the VM to come back")
+	}
+	if state.Attributes["power_on"] != "true" || state.Attributes["state"] != "running" {
+		t.Fatalf("expected the VM running again, got power_on %s and state %s", state.Attributes["power_on"], state.Attributes["state"])
+	}
+}
+
+func TestValidateKernelCmdline(t *testing.T) {
+	valid := []string{"", "mitigations=off", "nospectre_v2 mitigations=off", "console=ttyS0,115200 root=/dev/vda1"}
+	for _, cmdline := range valid {
+		if _, es := validateKernelCmdline(cmdline, "kernel_cmdline"); len(es) > 0 {
+			t.Errorf("%q: expected it to be valid, got %s", cmdline, es)
+		}
+	}
+
+	invalid := []string{"quiet; rm -rf /", "a && b", "$(reboot)", "`id`", "init=/bin/sh > /dev/null", "'quoted'", "line\nbreak"}
+	for _, cmdline := range invalid {
+		if _, es := validateKernelCmdline(cmdline, "kernel_cmdline"); len(es) == 0 {
+			t.Errorf("%q: expected it to be invalid", cmdline)
+		}
+	}
+}
+
+func TestVMInheritsGroupBillingCode(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	state := testVMRefresh(t, c, nil)
+
+	if code := state.Attributes["billing_code"]; code != "ops-123" {
+		t.Fatalf("expected the group's billing code ops-123, got %q", code)
+	}
+}
+
+func TestVMDestroyModes(t *testing.T) {
+	cases := []struct {
+		config  map[string]interface{}
+		request string
+		mode    string
+	}{
+		{map[string]interface{}{}, "DELETE /accounts/myaccount/groups/default/virtual_machines/1?purge=true", "purge"},
+		{map[string]interface{}{"purge": false}, "DELETE /accounts/myaccount/groups/default/virtual_machines/1", "delete"},
+		{map[string]interface{}{"destroy_mode": "delete"}, "DELETE /accounts/myaccount/groups/default/virtual_machines/1", "delete"},
+		{map[string]interface{}{"destroy_mode": "power_off"}, "PUT /accounts/myaccount/groups/default/virtual_machines/1", "power_off"},
+	}
+
+	for _, tc := range cases {
+		var requests []string
+		c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.RequestURI())
+			if r.Method == "DELETE" {
+				w.WriteHeader(http.StatusNoContent)
+			}
+		})
+
+		tc.config["group"] = "default"
+		d := testVMResourceData(t, tc.config)
+
+		if err := resourceBigvVMDelete(d, c); err != nil {
+			t.Fatal(err)
+		}
+		server.Close()
+
+		if len(requests) != 1 || requests[0] != tc.request {
+			t.Errorf("%s: expected %s, got %v", tc.mode, tc.request, requests)
+		}
+	}
+}
+
+func TestVMPowerOffDropsFromState(t *testing.T) {
+	c, server, sent, check := testBigvCalls(t, []testBigvCall{
+		{"PUT", "/accounts/myaccount/groups/default/virtual_machines/1", 0, ""},
+	})
+	defer server.Close()
+
+	d := testVMResourceData(t, map[string]interface{}{"group": "default", "destroy_mode": "power_off"})
+
+	var err error
+	logged := testCaptureLog(func() {
+		err = resourceBigvVMDelete(d, c)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	check()
+
+	// Only powered off, never deleted
+	if sent[0]["power_on"] != false {
+		t.Errorf("expected the VM to be powered off, got %v", sent[0])
+	}
+
+	// Terraform forgets it, but says where it's been left
+	if d.Id() != "" {
+		t.Error("expected the VM to be removed from state")
+	}
+	if !strings.Contains(logged, "[WARN] VM 1") || !strings.Contains(logged, "importing again") {
+		t.Errorf("expected a warning about the VM left behind, got %q", logged)
+	}
+}
+
+func TestVMDeleteNotFound(t *testing.T) {
+	c, server, _, check := testBigvCalls(t, []testBigvCall{
+		{"DELETE", "/accounts/myaccount/groups/default/virtual_machines/1?purge=true", http.StatusNotFound, ""},
+	})
+	defer server.Close()
+
+	d := testVMResourceData(t, map[string]interface{}{"group": "default"})
+
+	if err := resourceBigvVMDelete(d, c); err != nil {
+		t.Fatalf("expected deleting a VM that's already gone to succeed, got %s", err)
+	}
+	check()
+
+	if d.Id() != "" {
+		t.Error("expected the VM to be removed from state")
+	}
+}
+
+func TestVMApiTimeoutOverride(t *testing.T) {
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+		w.Write([]byte(`{}`))
+	})
+	defer server.Close()
+
+	c.httpTimeout = 1
+
+	cases := []struct {
+		override int
+		timesOut bool
+	}{
+		// The provider's timeout
+		{0, true},
+		{2, false},
+	}
+
+	for _, tc := range cases {
+		d := testVMResourceData(t, map[string]interface{}{"api_timeout_override": tc.override})
+
+		req, _ := http.NewRequest("GET", server.URL+"/virtual_machines/1", nil)
+		resp, err := vmDo(d, c, req)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		if tc.timesOut && err == nil {
+			t.Errorf("api_timeout_override %d: expected the provider's 1s timeout to apply", tc.override)
+		}
+		if !tc.timesOut && err != nil {
+			t.Errorf("api_timeout_override %d: expected the request to be given 2s, got %s", tc.override, err)
+		}
+	}
+}
+
+func TestVMApiTimeoutOverrideValidation(t *testing.T) {
+	for _, override := range []int{-1, 0, 30} {
+		rc, err := config.NewRawConfig(map[string]interface{}{"name": "web", "api_timeout_override": override})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, es := resourceBigvVM().Validate(terraform.NewResourceConfig(rc))
+		if override < 0 && len(es) == 0 {
+			t.Errorf("%d: expected a negative timeout to be rejected", override)
+		}
+		if override >= 0 && len(es) > 0 {
+			t.Errorf("%d: expected it to be valid, got %s", override, es)
+		}
+	}
+}
+
+// testVMImport
+// What terraform import then refresh makes of the VM with import id
+func testVMImport(t *testing.T, c *client, id string) (*terraform.InstanceState, error) {
+	d := resourceBigvVM().Data(&terraform.InstanceState{ID: id})
+
+	imported, err := resourceBigvVM().Importer.State(d, c)
+	if err != nil {
+		return nil, err
+	}
+	if len(imported) != 1 {
+		t.Fatalf("expected one VM to be imported, got %d", len(imported))
+	}
+
+	state := imported[0].State()
+	state.Meta = map[string]interface{}{"schema_version": "3"}
+
+	return testVMRefresh(t, c, state), nil
+}
+
+func TestVMImportById(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	state, err := testVMImport(t, c, "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"name":   "web",
+		"group":  "default",
+		"zone":   "york",
+		"cores":  "1",
+		"memory": "1024",
+		"ipv4":   "192.0.2.10",
+		"ipv6":   "2001:db8::10",
+		"os":     "bookworm",
+		// Only terraform knows these, so they're the defaults
+		"destroy_mode": "purge",
+		"purge":        "true",
+	}
+	for k, v := range expected {
+		if state.Attributes[k] != v {
+			t.Errorf("expected %s %s, got %q", k, v, state.Attributes[k])
+		}
+	}
+
+	// Importing a VM into config that matches it has nothing left to do
+	testVMPlanEmpty(t, testVMPlan(t, c, state, map[string]interface{}{"name": "web"}))
+}
+
+func TestVMImportByPath(t *testing.T) {
+	var lookups []string
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/accounts/myaccount/groups/default/virtual_machines/") {
+			lookups = append(lookups, r.URL.Path)
+			if strings.HasSuffix(r.URL.Path, "/web") {
+				w.Write([]byte(`{"id":1,"name":"web"}`))
+			} else {
+				w.WriteHeader(http.StatusNotFound)
+			}
+			return
+		}
+		fake.ServeHTTP(w, r)
+	})
+	defer server.Close()
+
+	state, err := testVMImport(t, c, "myaccount/default/web")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if state.ID != "1" || state.Attributes["name"] != "web" || state.Attributes["group"] != "default" {
+		t.Errorf("expected VM 1 named web in default, got %s named %s in %s", state.ID, state.Attributes["name"], state.Attributes["group"])
+	}
+	if len(lookups) != 1 || lookups[0] != "/accounts/myaccount/groups/default/virtual_machines/web" {
+		t.Errorf("expected the name to be looked up once in its group, got %v", lookups)
+	}
+
+	if _, err := testVMImport(t, c, "myaccount/default/missing"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected importing a VM bigv doesn't have to fail, got %v", err)
+	}
+}
+
+func TestVMImportInvalidIds(t *testing.T) {
+	requests := 0
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	defer server.Close()
+
+	cases := map[string]string{
+		"web":                      "numeric VM id or account/group/name",
+		"default/web":              "account/group/name",
+		"myaccount/default/web/01": "account/group/name",
+		"myaccount//web":           "account/group/name",
+		"/default/web":             "account/group/name",
+		"otheraccount/default/web": "not in the provider's account myaccount",
+		"myaccount/default/web01/": "account/group/name",
+	}
+
+	for id, message := range cases {
+		_, err := testVMImport(t, c, id)
+		if err == nil || !strings.Contains(err.Error(), message) {
+			t.Errorf("%s: expected an error about %s, got %v", id, message, err)
+		}
+	}
+
+	if requests != 0 {
+		t.Errorf("expected bad ids to be rejected without asking bigv, got %d requests", requests)
+	}
+}
+
+func TestWaitForBigvStatePollInterval(t *testing.T) {
+	var polls []time.Time
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		polls = append(polls, time.Now())
+		w.Write([]byte(fmt.Sprintf(`{"id":1,"name":"web","power_on":%t}`, len(polls) == 3)))
+	})
+	defer server.Close()
+
+	d := testVMResourceData(t, map[string]interface{}{})
+
+	interval := 100 * time.Millisecond
+	if err := waitForBigvState(context.Background(), d, c, waitForPowered, interval); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(polls) != 3 {
+		t.Fatalf("expected 3 polls, got %d", len(polls))
+	}
+	for i := 1; i < len(polls); i++ {
+		// Allowing for the ticker firing slightly before the request round trip lines up
+		if gap := polls[i].Sub(polls[i-1]); gap < interval*9/10 {
+			t.Errorf("poll %d: expected at least %s since the last, got %s", i+1, interval, gap)
+		}
+	}
+}
+
+func TestVMResizeUsesResizePollInterval(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	// A resize that polled at the provision interval would outlast the test
+	c.provisionPollInterval = time.Hour
+	c.resizePollInterval = 10 * time.Millisecond
+
+	state := testVMRefresh(t, c, nil)
+	diff := testVMPlan(t, c, state, map[string]interface{}{"name": "web", "cores": 2, "memory": 8192})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := resourceBigvVM().Apply(state, diff, c)
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the resize to poll every resize_poll_interval")
+	}
+
+	if len(fake.puts) != 1 || fake.puts[0].Cores != 2 || fake.restarting != 0 {
+		t.Fatalf("expected the resize to restart the VM and wait for it, got %#v", fake.puts)
+	}
+}
+
+func TestProviderPollIntervals(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, Provider().(*schema.Provider).Schema, map[string]interface{}{
+		"account":              "myaccount",
+		"api_key":              "key",
+		"resize_poll_interval": 30,
+	})
+
+	meta, err := providerConfigure(d, context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := meta.(*client)
+	if c.provisionPollInterval != 5*time.Second || c.resizePollInterval != 30*time.Second {
+		t.Errorf("expected polls every 5s provisioning and 30s resizing, got %s and %s", c.provisionPollInterval, c.resizePollInterval)
+	}
+
+	s := Provider().(*schema.Provider).Schema
+	for _, k := range []string{"provision_poll_interval", "resize_poll_interval"} {
+		for _, interval := range []int{0, 61} {
+			if _, es := s[k].ValidateFunc(interval, k); len(es) == 0 {
+				t.Errorf("%s %d: expected it to be outside 1 to 60 seconds", k, interval)
+			}
+		}
+	}
+}
+
+func TestVMCreatesProceedAfterOneFails(t *testing.T) {
+	for _, mode := range []string{"serial", "smart"} {
+		var mu sync.Mutex
+		loggedIn := false
+		c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			if r.URL.Path == "/session" {
+				if !loggedIn {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				w.Write([]byte("session"))
+				return
+			}
+			w.WriteHeader(http.StatusAccepted)
+		})
+
+		c.createPipelineMode = mode
+		c.creates = make(chan struct{}, 1)
+		c.session = ""
+
+		d := testVMResourceData(t, map[string]interface{}{})
+		create := func() error {
+			req, _ := http.NewRequest("POST", server.URL+"/accounts/myaccount/groups/default/vm_create", strings.NewReader(`{}`))
+			resp, err := requestBigvVMCreate(context.Background(), d, c, req)
+			if err == nil {
+				resp.Body.Close()
+			}
+			return err
+		}
+
+		// Failing to log in, holding both the session and create pipeline locks
+		if err := create(); err == nil {
+			t.Fatalf("%s: expected the create to fail logging in", mode)
+		}
+
+		mu.Lock()
+		loggedIn = true
+		mu.Unlock()
+
+		errs := make(chan error, 3)
+		for i := 0; i < 3; i++ {
+			go func() { errs <- create() }()
+		}
+		for i := 0; i < 3; i++ {
+			select {
+			case err := <-errs:
+				if err != nil {
+					t.Errorf("%s: %s", mode, err)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatalf("%s: expected creates after the failed one to get through", mode)
+			}
+		}
+
+		server.Close()
+	}
+}
+
+func TestVMRootPasswordSensitive(t *testing.T) {
+	fake := newTestBigvVMServer()
+	c, server := testBigvClient(fake.ServeHTTP)
+	defer server.Close()
+
+	// Shown as <sensitive> in plans
+	diff := testVMPlan(t, c, nil, map[string]interface{}{"name": "web"})
+	if password := diff.Attributes["root_password"]; password == nil || !password.Sensitive {
+		t.Errorf("expected root_password to be sensitive in the plan, got %#v", password)
+	}
+
+	// bigv never sends it back, so refreshes have to keep it for provisioners
+	state := testVMRefresh(t, c, nil)
+	state.Attributes["root_password"] = "s3cret"
+	state = testVMRefresh(t, c, state)
+
+	if state.Attributes["root_password"] != "s3cret" {
+		t.Errorf("expected the root password to stay in state, got %q", state.Attributes["root_password"])
+	}
+	if password := state.Ephemeral.ConnInfo["password"]; password != "s3cret" {
+		t.Errorf("expected provisioners to connect with the root password, got %q", password)
+	}
+
+	state.Attributes["public_key_auth_only"] = "true"
+	state = testVMRefresh(t, c, state)
+	if _, ok := state.Ephemeral.ConnInfo["password"]; ok {
+		t.Error("expected no password for a VM that only takes keys")
+	}
+}
+
+// testVMValidate
+// The errors terraform validate gives for the config, without asking bigv
+func testVMValidate(t *testing.T, raw map[string]interface{}) []error {
+	rc, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, es := resourceBigvVM().Validate(terraform.NewResourceConfig(rc))
+	return es
+}
+
+func TestVMValidatesIps(t *testing.T) {
+	cases := []struct {
+		raw   map[string]interface{}
+		valid bool
+	}{
+		{map[string]interface{}{"ipv4": "192.0.2.10", "ipv6": "2001:db8::10"}, true},
+		{map[string]interface{}{"ipv4": "256.0.0.1"}, false},
+		{map[string]interface{}{"ipv4": "not-an-ip"}, false},
+		{map[string]interface{}{"ipv4": "2001:db8::10"}, false},
+		{map[string]interface{}{"ipv6": "192.0.2.10"}, false},
+		{map[string]interface{}{"network_interface": []interface{}{map[string]interface{}{"ipv4": "192.0.2.30"}}}, true},
+		{map[string]interface{}{"network_interface": []interface{}{map[string]interface{}{"ipv4": "192.0.2.300"}}}, false},
+	}
+
+	for _, tc := range cases {
+		tc.raw["name"] = "web"
+		es := testVMValidate(t, tc.raw)
+		if tc.valid && len(es) > 0 {
+			t.Errorf("%v: expected it to be valid, got %s", tc.raw, es)
+		}
+		if !tc.valid && len(es) == 0 {
+			t.Errorf("%v: expected it to fail validation", tc.raw)
+		}
+	}
+}
+
+func TestVMValidatesZone(t *testing.T) {
+	cases := []struct {
+		zone  string
+		valid bool
+	}{
+		{"york", true},
+		{"manchester", true},
+		{"leeds", false},
+		{"York", false},
+		{"", false},
+	}
+
+	for _, tc := range cases {
+		es := testVMValidate(t, map[string]interface{}{"name": "web", "zone": tc.zone})
+		if tc.valid && len(es) > 0 {
+			t.Errorf("%q: expected it to be valid, got %s", tc.zone, es)
+		}
+		if !tc.valid && len(es) == 0 {
+			t.Errorf("%q: expected it to fail validation", tc.zone)
+		}
+	}
+}
+
+func TestZonesValidatedEverywhere(t *testing.T) {
+	cases := []struct {
+		name   string
+		schema map[string]*schema.Schema
+		key    string
+	}{
+		{"provider", Provider().(*schema.Provider).Schema, "default_zone"},
+		{"bigv_vm", resourceBigvVM().Schema, "zone"},
+		{"bigv_ip", resourceBigvIp().Schema, "zone"},
+		{"bigv_floating_ip", resourceBigvFloatingIp().Schema, "zone"},
+		{"bigv_migration", resourceBigvMigration().Schema, "target_zone"},
+		{"bigv_object_storage", resourceBigvObjectStorage().Schema, "region"},
+	}
+
+	for _, tc := range cases {
+		validate := tc.schema[tc.key].ValidateFunc
+		if validate == nil {
+			t.Errorf("%s: %s isn't validated", tc.name, tc.key)
+			continue
+		}
+		if _, es := validate("york", tc.key); len(es) > 0 {
+			t.Errorf("%s: expected york to be valid, got %s", tc.name, es)
+		}
+		if _, es := validate("leeds", tc.key); len(es) == 0 {
+			t.Errorf("%s: expected leeds to fail validation", tc.name)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package bigv
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+type bigvVpn struct {
+	Id              int    `json:"id,omitempty"`
+	Name            string `json:"name,omitempty"`
+	RemoteGatewayIp string `json:"remote_gateway_ip,omitempty"`
+	PreSharedKey    string `json:"pre_shared_key,omitempty"`
+	LocalCidr       string `json:"local_cidr,omitempty"`
+	RemoteCidr      string `json:"remote_cidr,omitempty"`
+	IkeVersion      int    `json:"ike_version,omitempty"`
+	IkeCipherSuite  string `json:"ike_cipher_suite,omitempty"`
+	// false has to reach bigv too, it's on unless told otherwise
+	DpdEnabled bool `json:"dpd_enabled"`
+
+	// Read Attributes
+	GatewayIp string `json:"gateway_ip,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+func resourceBigvVpn() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceBigvVpnCreate,
+		Read:   resourceBigvVpnRead,
+		Update: resourceBigvVpnUpdate,
+		Delete: resourceBigvVpnDelete,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"remote_gateway_ip": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateIP,
+				Description:  "The public address of the VPN gateway at the other end",
+			},
+			"pre_shared_key": &schema.Schema{
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
+			},
+			"local_cidr": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.CIDRNetwork(0, 128),
+				Description:  "The bigv side's network",
+			},
+			"remote_cidr": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.CIDRNetwork(0, 128),
+				Description:  "The network at the other end",
+			},
+			"ike_version": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      2,
+				ValidateFunc: validation.IntInSlice([]int{1, 2}),
+			},
+			"ike_cipher_suite": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "aes256-sha256-modp2048",
+				Description: "Encryption, integrity and DH group, which must match the other end's",
+			},
+			"dpd_enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Dead peer detection, restarting the tunnel when the other end stops answering",
+			},
+			"bigv_gateway_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"status": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func bigvVpnsUrl(bigvClient *client) string {
+	return fmt.Sprintf("%s/accounts/%s/vpns",
+		bigvClient.apiUri(),
+		bigvClient.account,
+	)
+}
+
+func vpnFromResource(d *schema.ResourceData) bigvVpn {
+	return bigvVpn{
+		Name:            d.Get("name").(string),
+		RemoteGatewayIp: d.Get("remote_gateway_ip").(string),
+		PreSharedKey:    d.Get("pre_shared_key").(string),
+		LocalCidr:       d.Get("local_cidr").(string),
+		RemoteCidr:      d.Get("remote_cidr").(string),
+		IkeVersion:      d.Get("ike_version").(int),
+		IkeCipherSuite:  d.Get("ike_cipher_suite").(string),
+		DpdEnabled:      d.Get("dpd_enabled").(bool),
+	}
+}
+
+func resourceBigvVpnCreate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vpn := &bigvVpn{}
+	if _, err := bigvClient.doJson("POST", bigvVpnsUrl(bigvClient), vpnFromResource(d), vpn); err != nil {
+		return err
+	}
+
+	if vpn.Id == 0 {
+		return fmt.Errorf("bigv didn't return an id for VPN %s", d.Get("name"))
+	}
+
+	d.SetId(strconv.Itoa(vpn.Id))
+
+	log.Printf("[DEBUG] Created BigV VPN, Id: %s", d.Id())
+
+	return resourceBigvVpnRead(d, meta)
+}
+
+// resourceBigvVpnRead
+// bigv never gives the pre-shared key back, so it's left as configured
+func resourceBigvVpnRead(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	vpn := &bigvVpn{}
+	status, err := bigvClient.doJson("GET", bigvVpnsUrl(bigvClient)+"/"+d.Id(), nil, vpn)
+	if status == http.StatusNotFound {
+		log.Printf("[WARN] VPN %s not found in bigv, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	d.Set("name", vpn.Name)
+	d.Set("remote_gateway_ip", vpn.RemoteGatewayIp)
+	d.Set("local_cidr", vpn.LocalCidr)
+	d.Set("remote_cidr", vpn.RemoteCidr)
+	d.Set("ike_version", vpn.IkeVersion)
+	d.Set("ike_cipher_suite", vpn.IkeCipherSuite)
+	d.Set("dpd_enabled", vpn.DpdEnabled)
+	d.Set("bigv_gateway_ip", vpn.GatewayIp)
+	d.Set("status", vpn.Status)
+
+	return nil
+}
+
+func resourceBigvVpnUpdate(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	if _, err := bigvClient.doJson("PUT", bigvVpnsUrl(bigvClient)+"/"+d.Id(), vpnFromResource(d), nil); err != nil {
+		return err
+	}
+
+	return resourceBigvVpnRead(d, meta)
+}
+
+func resourceBigvVpnDelete(d *schema.ResourceData, meta interface{}) error {
+	bigvClient := meta.(*client)
+
+	status, err := bigvClient.doJson("DELETE", bigvVpnsUrl(bigvClient)+"/"+d.Id(), nil, nil)
+	if status == http.StatusNotFound {
+		// Already gone, which is all we wanted
+		d.SetId("")
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package bigv
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestVpnRoundTrip(t *testing.T) {
+	vpn := `{"id":7,"name":"office","remote_gateway_ip":"198.51.100.1","local_cidr":"10.0.0.0/24",` +
+		`"remote_cidr":"10.1.0.0/24","ike_version":2,"ike_cipher_suite":"aes256-sha256-modp2048",` +
+		`"dpd_enabled":true,"gateway_ip":"192.0.2.1","status":"up"}`
+
+	d, sent := testBigvRoundTrip(t, resourceBigvVpn(), map[string]interface{}{
+		"name":              "office",
+		"remote_gateway_ip": "198.51.100.1",
+		"pre_shared_key":    "hunter2",
+		"local_cidr":        "10.0.0.0/24",
+		"remote_cidr":       "10.1.0.0/24",
+	}, map[string]interface{}{
+		"dpd_enabled": false,
+	}, []testBigvCall{
+		{"POST", "/accounts/myaccount/vpns", 0, vpn},
+		{"GET", "/accounts/myaccount/vpns/7", 0, vpn},
+		{"PUT", "/accounts/myaccount/vpns/7", 0, vpn},
+		{"GET", "/accounts/myaccount/vpns/7", 0, vpn},
+		{"DELETE", "/accounts/myaccount/vpns/7", http.StatusNoContent, ""},
+	})
+
+	testBigvSent(t, "create", sent[0], map[string]interface{}{
+		"name":              "office",
+		"remote_gateway_ip": "198.51.100.1",
+		"pre_shared_key":    "hunter2",
+		"local_cidr":        "10.0.0.0/24",
+		"remote_cidr":       "10.1.0.0/24",
+		"ike_version":       float64(2),
+		"ike_cipher_suite":  "aes256-sha256-modp2048",
+		"dpd_enabled":       true,
+	})
+
+	// bigv never sends the key back, so it has to survive the read to be sent again
+	update := sent[2]
+	if update["pre_shared_key"] != "hunter2" {
+		t.Errorf("expected the pre-shared key to be sent with the update, got %v", update["pre_shared_key"])
+	}
+	if v, ok := update["dpd_enabled"]; !ok || v != false {
+		t.Errorf("expected dead peer detection to be turned off, got %v", update)
+	}
+
+	if d.Get("bigv_gateway_ip") != "192.0.2.1" || d.Get("status") != "up" {
+		t.Errorf("expected an up VPN from 192.0.2.1, got %v from %v", d.Get("status"), d.Get("bigv_gateway_ip"))
+	}
+}
+
+func TestVpnNotFound(t *testing.T) {
+	testBigvNotFound(t, resourceBigvVpn(), map[string]interface{}{"name": "office"}, "7")
+}
@@ -0,0 +1,80 @@
+package bigv
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTotpCode(t *testing.T) {
+	// The SHA1 test vectors from RFC 6238, the secret being "12345678901234567890" in base32
+	secret := "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+	cases := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, c := range cases {
+		code, err := totpCode(secret, time.Unix(c.unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != c.code {
+			t.Errorf("at %d: expected %s, got %s", c.unix, c.code, code)
+		}
+	}
+}
+
+func TestTotpCodeSecretFormats(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	for _, secret := range []string{"gezd gnbv gy3t qojq gezd gnbv gy3t qojq", "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ===="} {
+		code, err := totpCode(secret, now)
+		if err != nil {
+			t.Fatalf("%s: %s", secret, err)
+		}
+		if code != "287082" {
+			t.Errorf("%s: expected 287082, got %s", secret, code)
+		}
+	}
+
+	if _, err := totpCode("not base32!", now); err == nil {
+		t.Error("expected an error for a secret that isn't base32")
+	}
+}
+
+func TestNewSessionSendsTotp(t *testing.T) {
+	var sent string
+	c, server := testBigvClient(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		sent = string(body)
+		w.Write([]byte("new-session"))
+	})
+	defer server.Close()
+
+	c.totp = "123456"
+	if err := c.newSession(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sent, `"2fa":"123456"`) {
+		t.Fatalf("expected the code in the credentials, got %s", sent)
+	}
+
+	c.totp = ""
+	if err := c.newSession(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(sent, "2fa") {
+		t.Fatalf("expected no code without 2FA, got %s", sent)
+	}
+}